@@ -1,9 +1,12 @@
 package favor
 
 import (
-// "fmt"
+	"encoding/json"
+	"fmt"
+	"net/url"
 )
 
+// Address represents a delivery address saved to a user's account
 type Address struct {
 	ID         string `json:"id"`
 	CustomerID string `json:"customer_id"`
@@ -14,3 +17,99 @@ type Address struct {
 	Apartment  string `json:"apartment"`
 	Notes      string `json:"notes"`
 }
+
+// CreateFormString turns an Address struct into an appropriate POST form payload.
+// The ID and CustomerID are left out, since the server decides those for us.
+func (a Address) CreateFormString() url.Values {
+	u := url.Values{}
+	u.Add("lat", a.Lat)
+	u.Add("lng", a.Lng)
+	u.Add("street", a.Street)
+	u.Add("zipcode", a.Zipcode)
+	u.Add("apartment", a.Apartment)
+	u.Add("notes", a.Notes)
+	return u
+}
+
+// ListAddresses is used to retrieve the addresses saved to the token's account.
+func (c Client) ListAddresses() ([]Address, error) {
+	uri := c.BuildURL("addresses", map[string]string{})
+	addressData, err := c.makeAPIRequest("get", uri)
+	if err != nil {
+		return nil, err
+	}
+	ar := struct {
+		Addresses []Address `json:"addresses"`
+	}{}
+
+	err = json.Unmarshal(addressData, &ar)
+	if err != nil {
+		return nil, err
+	}
+	return ar.Addresses, nil
+}
+
+// CreateAddress saves a new address to the token's account, and returns the
+// address as the server saw it, ID and all.
+func (c Client) CreateAddress(a Address) (Address, error) {
+	uri := c.BuildURL("addresses", map[string]string{})
+	addressData, err := c.makeAPIRequestWithBody("post", uri, a.CreateFormString())
+	if err != nil {
+		return Address{}, err
+	}
+	return unmarshalAddress(addressData)
+}
+
+// UpdateAddress updates an existing address on the token's account. The
+// provided address must have its ID set.
+func (c Client) UpdateAddress(a Address) (Address, error) {
+	if a.ID == "" {
+		return Address{}, fmt.Errorf("An address ID is required to update an address.")
+	}
+	uri := c.BuildURL(fmt.Sprintf("addresses/%v", a.ID), map[string]string{})
+	addressData, err := c.makeAPIRequestWithBody("put", uri, a.CreateFormString())
+	if err != nil {
+		return Address{}, err
+	}
+	return unmarshalAddress(addressData)
+}
+
+// DeleteAddress removes an address from the token's account.
+func (c Client) DeleteAddress(id string) error {
+	if id == "" {
+		return fmt.Errorf("An address ID is required to delete an address.")
+	}
+	uri := c.BuildURL(fmt.Sprintf("addresses/%v", id), map[string]string{})
+	_, err := c.makeAPIRequest("delete", uri)
+	return err
+}
+
+func unmarshalAddress(addressData []byte) (Address, error) {
+	ar := struct {
+		Address Address `json:"address"`
+	}{}
+
+	err := json.Unmarshal(addressData, &ar)
+	if err != nil {
+		return Address{}, err
+	}
+	return ar.Address, nil
+}
+
+// FromAddress returns a copy of the RequestFavor with its delivery fields
+// filled in from a saved Address, so that nobody has to copy the street,
+// zipcode, and coordinates over by hand.
+func (rf RequestFavor) FromAddress(a Address) (RequestFavor, error) {
+	loc, err := a.Location()
+	if err != nil {
+		return RequestFavor{}, err
+	}
+
+	rf.Lat = loc.Lat
+	rf.Lng = loc.Lng
+	rf.Street = a.Street
+	rf.Zipcode = a.Zipcode
+	rf.Apt = a.Apartment
+	rf.Notes = a.Notes
+	return rf, nil
+}
@@ -0,0 +1,147 @@
+package favor
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+var dummyAddressJSON = `{
+	"id": "42",
+	"customer_id": "7",
+	"lat": "-33.865143",
+	"lng": "151.209900",
+	"street": "42 Wallaby Way",
+	"zipcode": "2000",
+	"apartment": "123",
+	"notes": "In west Philadelphia, born and raised."
+}`
+
+var dummyAddress = Address{
+	ID:         "42",
+	CustomerID: "7",
+	Lat:        "-33.865143",
+	Lng:        "151.209900",
+	Street:     "42 Wallaby Way",
+	Zipcode:    "2000",
+	Apartment:  "123",
+	Notes:      "In west Philadelphia, born and raised.",
+}
+
+func TestListAddresses(t *testing.T) {
+	s := setupMockClient(t, respondWith(`{"addresses": [`+dummyAddressJSON+`]}`))
+
+	actual, err := s.ListAddresses()
+	if err != nil {
+		t.Errorf("ListAddresses failed with the following error: %v", err)
+	}
+	if !reflect.DeepEqual([]Address{dummyAddress}, actual) {
+		t.Errorf("Retrieved addresses differ from expected result.\n")
+		t.Errorf("Expected:\n%v+ \n", []Address{dummyAddress})
+		t.Errorf("Received:\n%v+ \n", actual)
+	}
+}
+
+func TestCreateAddress(t *testing.T) {
+	s := setupMockClient(t, respondWith(`{"address": `+dummyAddressJSON+`}`))
+
+	newAddress := dummyAddress
+	newAddress.ID = ""
+	newAddress.CustomerID = ""
+
+	actual, err := s.CreateAddress(newAddress)
+	if err != nil {
+		t.Errorf("CreateAddress failed with the following error: %v", err)
+	}
+	if !reflect.DeepEqual(dummyAddress, actual) {
+		t.Errorf("Created address differs from expected result.\n")
+		t.Errorf("Expected:\n%v+ \n", dummyAddress)
+		t.Errorf("Received:\n%v+ \n", actual)
+	}
+
+	_, err = s.UpdateAddress(newAddress)
+	if err == nil {
+		t.Errorf("UpdateAddress should fail for an address without an ID")
+	}
+}
+
+func TestUpdateAddress(t *testing.T) {
+	s, recorded := setupRecordingClient(t, `{"address": `+dummyAddressJSON+`}`)
+
+	actual, err := s.UpdateAddress(dummyAddress)
+	if err != nil {
+		t.Errorf("UpdateAddress failed with the following error: %v", err)
+	}
+	if !reflect.DeepEqual(dummyAddress, actual) {
+		t.Errorf("Updated address differs from expected result.\n")
+		t.Errorf("Expected:\n%v+ \n", dummyAddress)
+		t.Errorf("Received:\n%v+ \n", actual)
+	}
+
+	if recorded.Method != "PUT" || recorded.Path != "/api/v5/addresses/42" {
+		t.Errorf("Expected PUT /api/v5/addresses/42, but the server received %v %v", recorded.Method, recorded.Path)
+	}
+	expectedForm := url.Values{
+		"lat":       {"-33.865143"},
+		"lng":       {"151.209900"},
+		"street":    {"42 Wallaby Way"},
+		"zipcode":   {"2000"},
+		"apartment": {"123"},
+		"notes":     {"In west Philadelphia, born and raised."},
+	}
+	if !reflect.DeepEqual(expectedForm, recorded.Form) {
+		t.Errorf("Sent form differs from expected result.\n")
+		t.Errorf("Expected:\n%v+ \n", expectedForm)
+		t.Errorf("Received:\n%v+ \n", recorded.Form)
+	}
+}
+
+func TestDeleteAddress(t *testing.T) {
+	s, recorded := setupRecordingClient(t, `{}`)
+
+	if err := s.DeleteAddress("42"); err != nil {
+		t.Errorf("DeleteAddress failed with the following error: %v", err)
+	}
+	if recorded.Method != "DELETE" || recorded.Path != "/api/v5/addresses/42" {
+		t.Errorf("Expected DELETE /api/v5/addresses/42, but the server received %v %v", recorded.Method, recorded.Path)
+	}
+	if len(recorded.Form) != 0 {
+		t.Errorf("DeleteAddress shouldn't send a form, but sent %v", recorded.Form)
+	}
+
+	*recorded = recordedRequest{}
+	if err := s.DeleteAddress(""); err == nil {
+		t.Errorf("DeleteAddress should refuse an empty ID")
+	}
+	if recorded.Method != "" {
+		t.Errorf("DeleteAddress shouldn't send anything without an ID, but sent %v %v", recorded.Method, recorded.Path)
+	}
+}
+
+func TestFromAddress(t *testing.T) {
+	rf := RequestFavor{Title: "Salty Greg's Frog House", Wants: "Frog's legs"}
+	expected := RequestFavor{
+		Title:   "Salty Greg's Frog House",
+		Wants:   "Frog's legs",
+		Lat:     -33.865143,
+		Lng:     151.2099,
+		Street:  "42 Wallaby Way",
+		Zipcode: "2000",
+		Apt:     "123",
+		Notes:   "In west Philadelphia, born and raised.",
+	}
+
+	actual, err := rf.FromAddress(dummyAddress)
+	if err != nil {
+		t.Errorf("FromAddress failed with the following error: %v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected:\n%v+ \n", expected)
+		t.Errorf("Received:\n%v+ \n", actual)
+	}
+
+	_, err = rf.FromAddress(Address{Lat: "north", Lng: "south"})
+	if err == nil {
+		t.Errorf("FromAddress should fail for unparseable coordinates")
+	}
+}
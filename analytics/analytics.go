@@ -0,0 +1,235 @@
+// Package analytics answers the question finance asks every month: how much
+// are we spending on deliveries, and where is it going? Analyze takes a pile of
+// favors, usually out of a store, and adds up their receipts by month,
+// merchant and customer.
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// Amounts is a breakdown of what was spent, in cents.
+type Amounts struct {
+	Price          int64 `json:"price_cents"`
+	Tip            int64 `json:"tip_cents"`
+	DeliveryCharge int64 `json:"delivery_charge_cents"`
+	CardFee        int64 `json:"card_fee_cents"`
+	Total          int64 `json:"total_cents"`
+}
+
+func (a *Amounts) add(ra favor.ReceiptAmounts) {
+	a.Price += ra.Price
+	a.Tip += ra.Tip
+	a.DeliveryCharge += ra.DeliveryCharge
+	a.CardFee += ra.CcFeeAmount
+	a.Total += ra.Total()
+}
+
+// dividedBy returns the amounts divided by n, rounded to the nearest cent.
+func (a Amounts) dividedBy(n int) Amounts {
+	if n == 0 {
+		return Amounts{}
+	}
+	div := func(c int64) int64 {
+		if c < 0 {
+			return -((-c + int64(n)/2) / int64(n))
+		}
+		return (c + int64(n)/2) / int64(n)
+	}
+	return Amounts{div(a.Price), div(a.Tip), div(a.DeliveryCharge), div(a.CardFee), div(a.Total)}
+}
+
+// Group is the spending for some set of favors, like all the ones from a
+// month or a merchant. Key identifies the group, and Name is something more
+// readable, when there is one.
+type Group struct {
+	Key      string  `json:"key"`
+	Name     string  `json:"name,omitempty"`
+	Count    int     `json:"count"`
+	Totals   Amounts `json:"totals"`
+	Averages Amounts `json:"averages"`
+}
+
+// TipBucket counts how many favors tipped within a range of percentages of
+// the price. Max is exclusive, and zero for the last bucket, which has no top.
+type TipBucket struct {
+	Label string  `json:"label"`
+	Min   float64 `json:"min_percent"`
+	Max   float64 `json:"max_percent,omitempty"`
+	Count int     `json:"count"`
+}
+
+// Report is everything Analyze works out. Merchants and Customers are sorted
+// by how much was spent, most first, and Months oldest first. Favors without a
+// receipt, or with one that can't be read, aren't counted anywhere but
+// Skipped.
+type Report struct {
+	Overall        Group       `json:"overall"`
+	Months         []Group     `json:"months"`
+	Merchants      []Group     `json:"merchants"`
+	Customers      []Group     `json:"customers"`
+	TopMerchants   []Group     `json:"top_merchants"`
+	TipPercentages []TipBucket `json:"tip_percentages"`
+	Skipped        int         `json:"skipped"`
+}
+
+// DefaultTipEdges are where the tip percentage buckets are split, unless
+// Options says otherwise.
+var DefaultTipEdges = []float64{5, 10, 15, 20, 25}
+
+// Options tweak what Analyze does. Location is the time zone months are
+// counted in, UTC by default. TopN is how many merchants make TopMerchants,
+// five by default. TipEdges splits the tip percentage buckets, and defaults
+// to DefaultTipEdges.
+type Options struct {
+	Location *time.Location
+	TopN     int
+	TipEdges []float64
+}
+
+// grouper adds favors up into groups by key.
+type grouper struct {
+	groups map[string]*Group
+}
+
+func (g *grouper) add(key, name string, ra favor.ReceiptAmounts) {
+	if g.groups == nil {
+		g.groups = map[string]*Group{}
+	}
+	group, ok := g.groups[key]
+	if !ok {
+		group = &Group{Key: key, Name: name}
+		g.groups[key] = group
+	}
+	if group.Name == "" {
+		group.Name = name
+	}
+	group.Count++
+	group.Totals.add(ra)
+}
+
+// list returns the groups with their averages worked out, sorted by less.
+func (g *grouper) list(less func(a, b Group) bool) []Group {
+	groups := []Group{}
+	for _, group := range g.groups {
+		group.Averages = group.Totals.dividedBy(group.Count)
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return less(groups[i], groups[j])
+	})
+	return groups
+}
+
+func byKey(a, b Group) bool {
+	return a.Key < b.Key
+}
+
+func bySpending(a, b Group) bool {
+	if a.Totals.Total != b.Totals.Total {
+		return a.Totals.Total > b.Totals.Total
+	}
+	return a.Key < b.Key
+}
+
+// merchantOf returns the key and name of the merchant a favor was from. Favors
+// that weren't from a known merchant are grouped by their title, which is
+// usually the merchant's name anyway.
+func merchantOf(f favor.Favor) (string, string) {
+	id := f.MerchantID
+	if id == "" {
+		id = f.Merchant.ID
+	}
+	name := f.Merchant.Name
+	if name == "" {
+		name = f.Title
+	}
+	if id == "" {
+		return "title:" + strings.ToLower(strings.TrimSpace(f.Title)), name
+	}
+	return id, name
+}
+
+func customerOf(f favor.Favor) (string, string) {
+	if f.Customer.ID == "" {
+		return "unknown", ""
+	}
+	return f.Customer.ID, strings.TrimSpace(f.Customer.Forename + " " + f.Customer.Surname)
+}
+
+func monthOf(f favor.Favor, loc *time.Location) string {
+	if f.CreatedAt <= 0 {
+		return "unknown"
+	}
+	return time.Unix(int64(f.CreatedAt), 0).In(loc).Format("2006-01")
+}
+
+// tipBuckets makes empty buckets for the edges.
+func tipBuckets(edges []float64) []TipBucket {
+	buckets := []TipBucket{}
+	lower := 0.0
+	for _, edge := range edges {
+		buckets = append(buckets, TipBucket{Label: fmt.Sprintf("%g-%g%%", lower, edge), Min: lower, Max: edge})
+		lower = edge
+	}
+	return append(buckets, TipBucket{Label: fmt.Sprintf("%g%%+", lower), Min: lower})
+}
+
+// Analyze adds up the favors' receipts.
+func Analyze(favors []favor.Favor, opts Options) Report {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	topN := opts.TopN
+	if topN <= 0 {
+		topN = 5
+	}
+	edges := opts.TipEdges
+	if len(edges) == 0 {
+		edges = DefaultTipEdges
+	}
+
+	report := Report{Overall: Group{Key: "overall"}, TipPercentages: tipBuckets(edges)}
+	months, merchants, customers := &grouper{}, &grouper{}, &grouper{}
+	for _, f := range favors {
+		ra, err := f.Receipt.Amounts()
+		if err != nil || ra.Total() == 0 {
+			report.Skipped++
+			continue
+		}
+
+		report.Overall.Count++
+		report.Overall.Totals.add(ra)
+		months.add(monthOf(f, loc), "", ra)
+		key, name := merchantOf(f)
+		merchants.add(key, name, ra)
+		key, name = customerOf(f)
+		customers.add(key, name, ra)
+
+		if ra.Price > 0 {
+			percent := float64(ra.Tip) / float64(ra.Price) * 100
+			for i := len(report.TipPercentages) - 1; i >= 0; i-- {
+				if percent >= report.TipPercentages[i].Min {
+					report.TipPercentages[i].Count++
+					break
+				}
+			}
+		}
+	}
+
+	report.Overall.Averages = report.Overall.Totals.dividedBy(report.Overall.Count)
+	report.Months = months.list(byKey)
+	report.Merchants = merchants.list(bySpending)
+	report.Customers = customers.list(bySpending)
+	report.TopMerchants = report.Merchants
+	if len(report.TopMerchants) > topN {
+		report.TopMerchants = report.TopMerchants[:topN]
+	}
+	return report
+}
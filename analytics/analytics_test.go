@@ -0,0 +1,129 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/favortest"
+)
+
+var (
+	march = time.Date(2016, time.March, 9, 12, 0, 0, 0, time.UTC)
+	april = time.Date(2016, time.April, 9, 12, 0, 0, 0, time.UTC)
+)
+
+// Analyze names customers after their forename and surname, so the surnames
+// are their IDs to keep them apart.
+var (
+	jeff10 = favor.User{ID: "10", Forename: "Jeff", Surname: "10"}
+	jeff11 = favor.User{ID: "11", Forename: "Jeff", Surname: "11"}
+)
+
+func testFavors() []favor.Favor {
+	return favortest.Favors(
+		favortest.Spec{ID: "1", MerchantID: "2", Title: "Torchy's Tacos", Customer: jeff10, Created: march, Price: "10.00", Tip: "2.00"},
+		favortest.Spec{ID: "2", MerchantID: "2", Title: "Torchy's Tacos", Customer: jeff11, Created: march, Price: "20.00", Tip: "1.00"},
+		favortest.Spec{ID: "3", MerchantID: "3", Title: "Kerbey Lane", Customer: jeff10, Created: april, Price: "30.00", Tip: "9.00"},
+		favortest.Spec{ID: "4", Title: "never paid for"},
+		favortest.Spec{ID: "5", Title: "Via 313", Customer: jeff10, Created: april, Price: "8.00", Tip: "$nope"},
+	)
+}
+
+func TestAnalyze(t *testing.T) {
+	r := Analyze(testFavors(), Options{})
+
+	assert.Equal(t, 2, r.Skipped)
+	assert.Equal(t, 3, r.Overall.Count)
+	expected := Amounts{Price: 6000, Tip: 1200, DeliveryCharge: 1500, CardFee: 150, Total: 8850}
+	assert.Equal(t, expected, r.Overall.Totals)
+	assert.Equal(t, Amounts{Price: 2000, Tip: 400, DeliveryCharge: 500, CardFee: 50, Total: 2950}, r.Overall.Averages)
+
+	assert.Len(t, r.Months, 2)
+	assert.Equal(t, "2016-03", r.Months[0].Key)
+	assert.Equal(t, 2, r.Months[0].Count)
+	assert.Equal(t, int64(3000), r.Months[0].Totals.Price)
+	assert.Equal(t, int64(1500), r.Months[0].Averages.Price)
+	assert.Equal(t, "2016-04", r.Months[1].Key)
+
+	assert.Len(t, r.Merchants, 2)
+	assert.Equal(t, "3", r.Merchants[0].Key)
+	assert.Equal(t, "Kerbey Lane", r.Merchants[0].Name)
+	assert.Equal(t, int64(4450), r.Merchants[0].Totals.Total)
+	assert.Equal(t, "2", r.Merchants[1].Key)
+	assert.Equal(t, int64(4400), r.Merchants[1].Totals.Total)
+	assert.Equal(t, int64(2200), r.Merchants[1].Averages.Total)
+
+	assert.Len(t, r.Customers, 2)
+	assert.Equal(t, "10", r.Customers[0].Key)
+	assert.Equal(t, "Jeff 10", r.Customers[0].Name)
+	assert.Equal(t, 2, r.Customers[0].Count)
+
+	counts := map[string]int{}
+	for _, b := range r.TipPercentages {
+		counts[b.Label] = b.Count
+	}
+	assert.Equal(t, map[string]int{"0-5%": 0, "5-10%": 1, "10-15%": 0, "15-20%": 0, "20-25%": 1, "25%+": 1}, counts)
+}
+
+func TestAnalyzeOptions(t *testing.T) {
+	pacific, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("no time zone database here")
+	}
+	firstOfApril := time.Date(2016, time.April, 1, 3, 0, 0, 0, time.UTC)
+	favors := favortest.Favors(
+		favortest.Spec{ID: "1", MerchantID: "2", Title: "Torchy's Tacos", Customer: jeff10, Created: firstOfApril, Price: "10.00", Tip: "1.00"},
+		favortest.Spec{ID: "2", Title: "Via 313", Customer: jeff10, Created: firstOfApril, Price: "10.00", Tip: "1.00"},
+	)
+
+	r := Analyze(favors, Options{Location: pacific, TopN: 1, TipEdges: []float64{10}})
+	assert.Equal(t, "2016-03", r.Months[0].Key)
+	assert.Len(t, r.TopMerchants, 1)
+	assert.Len(t, r.Merchants, 2)
+	assert.Equal(t, "title:via 313", r.Merchants[1].Key)
+	assert.Equal(t, []TipBucket{
+		{Label: "0-10%", Min: 0, Max: 10, Count: 0},
+		{Label: "10%+", Min: 10, Count: 2},
+	}, r.TipPercentages)
+}
+
+func TestAnalyzeNothing(t *testing.T) {
+	r := Analyze(nil, Options{})
+	assert.Equal(t, 0, r.Overall.Count)
+	assert.Equal(t, Amounts{}, r.Overall.Averages)
+	assert.NotNil(t, r.Months)
+	assert.Len(t, r.TipPercentages, len(DefaultTipEdges)+1)
+}
+
+func TestWriteJSON(t *testing.T) {
+	r := Analyze(testFavors(), Options{})
+	buf := &bytes.Buffer{}
+	assert.Nil(t, r.WriteJSON(buf))
+
+	decoded := Report{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, r, decoded)
+	assert.Contains(t, buf.String(), `"total_cents": 8850`)
+}
+
+func TestWriteCSV(t *testing.T) {
+	r := Analyze(testFavors(), Options{})
+	buf := &bytes.Buffer{}
+	assert.Nil(t, r.WriteCSV(buf))
+
+	rows, err := csv.NewReader(buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, []string{"overall", "overall", "", "3", "60.00", "12.00", "15.00", "1.50", "88.50", "20.00", "4.00", "5.00", "0.50", "29.50"}, rows[1])
+	assert.Equal(t, "month", rows[2][0])
+	assert.Equal(t, "2016-03", rows[2][1])
+	last := rows[len(rows)-1]
+	assert.Equal(t, []string{"tip_percentage", "25%+", "1"}, []string{last[0], last[1], last[3]})
+	// 1 header, 1 overall, 2 months, 2 merchants, 2 customers, 2 top merchants, 6 buckets
+	assert.Len(t, rows, 16)
+}
@@ -0,0 +1,65 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// WriteJSON writes the report as indented JSON. Amounts are in cents.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// csvHeader is the first row WriteCSV writes.
+var csvHeader = []string{
+	"section", "key", "name", "count",
+	"total_price", "total_tip", "total_delivery_charge", "total_card_fee", "total",
+	"average_price", "average_tip", "average_delivery_charge", "average_card_fee", "average_total",
+}
+
+func groupRow(section string, g Group) []string {
+	return []string{
+		section, g.Key, g.Name, strconv.Itoa(g.Count),
+		favor.FormatCents(g.Totals.Price), favor.FormatCents(g.Totals.Tip), favor.FormatCents(g.Totals.DeliveryCharge), favor.FormatCents(g.Totals.CardFee), favor.FormatCents(g.Totals.Total),
+		favor.FormatCents(g.Averages.Price), favor.FormatCents(g.Averages.Tip), favor.FormatCents(g.Averages.DeliveryCharge), favor.FormatCents(g.Averages.CardFee), favor.FormatCents(g.Averages.Total),
+	}
+}
+
+// WriteCSV writes the report as one CSV table, so it can go straight into a
+// spreadsheet. The section column says which part of the report a row is
+// from: overall, month, merchant, customer, top_merchant or tip_percentage.
+// Amounts are in dollars. Tip percentage rows only have a key, which is the
+// bucket's label, and a count.
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{csvHeader, groupRow("overall", r.Overall)}
+	sections := []struct {
+		name   string
+		groups []Group
+	}{
+		{"month", r.Months},
+		{"merchant", r.Merchants},
+		{"customer", r.Customers},
+		{"top_merchant", r.TopMerchants},
+	}
+	for _, section := range sections {
+		for _, g := range section.groups {
+			rows = append(rows, groupRow(section.name, g))
+		}
+	}
+	for _, b := range r.TipPercentages {
+		row := make([]string, len(csvHeader))
+		row[0], row[1], row[3] = "tip_percentage", b.Label, strconv.Itoa(b.Count)
+		rows = append(rows, row)
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
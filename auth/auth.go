@@ -0,0 +1,117 @@
+// Package auth implements the phone verification handshake the Favor app uses
+// to log in, so that a favorToken can be retrieved without digging it out of
+// intercepted requests. It goes like this: we ask Favor to text a code to a
+// phone number, somebody reads that code off their phone, and we hand it back
+// to Favor in exchange for a token.
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// DefaultBaseURL is where the Favor app sends its login requests.
+const DefaultBaseURL = "https://api.askfavor.com/api/v5/"
+
+// Authenticator is anything that can carry out the login handshake.
+type Authenticator interface {
+	// RequestCode asks for a verification code to be sent to the phone number.
+	RequestCode(phone string) error
+	// SubmitCode exchanges a verification code for a favorToken.
+	SubmitCode(phone, code string) (string, error)
+}
+
+// HTTPAuthenticator carries out the login handshake against the Favor API,
+// or anything else living at BaseURL that speaks the same language.
+type HTTPAuthenticator struct {
+	BaseURL string
+	Client  http.Client
+}
+
+// New is a constructor function returning an HTTPAuthenticator pointed at
+// the Favor API.
+func New() *HTTPAuthenticator {
+	return &HTTPAuthenticator{BaseURL: DefaultBaseURL}
+}
+
+func (h HTTPAuthenticator) post(endpoint string, body url.Values) ([]byte, error) {
+	base := h.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	if !strings.HasSuffix(base, "/") {
+		base = base + "/"
+	}
+
+	req, err := http.NewRequest("POST", base+endpoint, strings.NewReader(body.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("Login request failed to build and returned this error:\n %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := h.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Login request failed to complete and returned this error:\n %v", err)
+	}
+	defer res.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Login response failed to read and returned this error:\n %v", err)
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("Login request to %v failed with status %v: %v", endpoint, res.StatusCode, strings.TrimSpace(string(responseBody)))
+	}
+	return responseBody, nil
+}
+
+// RequestCode asks Favor to text a verification code to the phone number.
+func (h HTTPAuthenticator) RequestCode(phone string) error {
+	_, err := h.post("auth/request_code", url.Values{"phone": []string{phone}})
+	return err
+}
+
+// SubmitCode exchanges the verification code texted to the phone number for a favorToken.
+func (h HTTPAuthenticator) SubmitCode(phone, code string) (string, error) {
+	responseBody, err := h.post("auth/verify", url.Values{"phone": []string{phone}, "code": []string{code}})
+	if err != nil {
+		return "", err
+	}
+
+	tr := struct {
+		Token string `json:"token"`
+	}{}
+	err = json.Unmarshal(responseBody, &tr)
+	if err != nil {
+		return "", err
+	}
+	if tr.Token == "" {
+		return "", fmt.Errorf("The login response did not include a token.")
+	}
+	return tr.Token, nil
+}
+
+// Login carries out the full handshake for a phone number, and returns a Client
+// ready to make requests with the resulting token. The code function is called
+// after the code has been requested, and should return whatever was texted to
+// the phone, whether that means prompting a human or reading it from somewhere.
+func Login(a Authenticator, phone string, code func() (string, error)) (*favor.Client, error) {
+	if err := a.RequestCode(phone); err != nil {
+		return nil, err
+	}
+	c, err := code()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieving the verification code failed with this error:\n %v", err)
+	}
+	token, err := a.SubmitCode(phone, strings.TrimSpace(c))
+	if err != nil {
+		return nil, err
+	}
+	return favor.New(token)
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var dummyToken = "thisisarandomstringfortestinglol"
+
+func TestHTTPAuthenticatorLogin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/auth/request_code":
+			if r.PostForm.Get("phone") != "5124206969" {
+				w.WriteHeader(400)
+				return
+			}
+			fmt.Fprintln(w, `{"success": true}`)
+		case "/auth/verify":
+			if r.PostForm.Get("code") != "1234" {
+				w.WriteHeader(401)
+				fmt.Fprintln(w, `{"error": "bad code"}`)
+				return
+			}
+			fmt.Fprintf(w, `{"token": %q}`, dummyToken)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	h := &HTTPAuthenticator{BaseURL: server.URL}
+
+	c, err := Login(h, "5124206969", func() (string, error) { return " 1234\n", nil })
+	assert.Nil(t, err)
+	if assert.NotNil(t, c) {
+		assert.Equal(t, dummyToken, c.Token)
+	}
+
+	_, err = Login(h, "5124206969", func() (string, error) { return "9999", nil })
+	assert.NotNil(t, err, "Login should fail with the wrong code")
+
+	_, err = Login(h, "nope", func() (string, error) { return "1234", nil })
+	assert.NotNil(t, err, "Login should fail when the code request fails")
+}
+
+func TestFakeLogin(t *testing.T) {
+	f := NewFake("1234", dummyToken)
+
+	_, err := f.SubmitCode("5124206969", "1234")
+	assert.NotNil(t, err, "Fake should reject codes that were never requested")
+
+	c, err := Login(f, "5124206969", func() (string, error) {
+		assert.True(t, f.Requested("5124206969"))
+		return "1234", nil
+	})
+	assert.Nil(t, err)
+	if assert.NotNil(t, c) {
+		assert.Equal(t, dummyToken, c.Token)
+	}
+	assert.False(t, f.Requested("5124206969"))
+
+	_, err = Login(NewFake("1234", "short"), "5124206969", func() (string, error) { return "1234", nil })
+	assert.NotNil(t, err, "Login should refuse to build a client with a bad token")
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Fake is an Authenticator that never talks to anybody, for use in tests.
+// Any phone number that has requested a code can exchange Code for Token.
+type Fake struct {
+	Code  string
+	Token string
+
+	lock      sync.Mutex
+	requested map[string]bool
+}
+
+// NewFake is a constructor function returning a Fake that accepts the given
+// code and hands out the given token.
+func NewFake(code, token string) *Fake {
+	return &Fake{Code: code, Token: token, requested: map[string]bool{}}
+}
+
+// RequestCode pretends to text a code to the phone number.
+func (f *Fake) RequestCode(phone string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if phone == "" {
+		return fmt.Errorf("A phone number is required to request a code.")
+	}
+	if f.requested == nil {
+		f.requested = map[string]bool{}
+	}
+	f.requested[phone] = true
+	return nil
+}
+
+// SubmitCode returns Token if the phone number requested a code and the code matches.
+func (f *Fake) SubmitCode(phone, code string) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if !f.requested[phone] {
+		return "", fmt.Errorf("No code has been requested for %v.", phone)
+	}
+	if code != f.Code {
+		return "", fmt.Errorf("The code provided for %v is incorrect.", phone)
+	}
+	delete(f.requested, phone)
+	return f.Token, nil
+}
+
+// Requested returns whether a code has been requested for the phone number
+// and not yet used.
+func (f *Fake) Requested(phone string) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.requested[phone]
+}
@@ -0,0 +1,164 @@
+// Package budget keeps people and teams from ordering past what they're
+// allowed to spend. A Policy wraps a client's PlaceFavor: before anything is
+// ordered, it adds up what's already been spent this day, week or month from
+// the receipts in a store, estimates what the new favor will cost, and blocks
+// it or asks for approval if that would go over a limit.
+package budget
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// Period is how long a limit lasts before it starts over.
+type Period int
+
+// These are the periods a limit can be for. Weeks start on Monday.
+const (
+	Daily Period = iota
+	Weekly
+	Monthly
+)
+
+func (p Period) String() string {
+	return [...]string{"daily", "weekly", "monthly"}[p]
+}
+
+// Start returns when the period containing t began, in t's location.
+func (p Period) Start(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch p {
+	case Weekly:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case Monthly:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+// Action is what happens when a limit would be exceeded.
+type Action int
+
+// Block refuses to place the favor at all. RequireApproval places it only if
+// the policy's Approver says so.
+const (
+	Block Action = iota
+	RequireApproval
+)
+
+func (a Action) String() string {
+	return [...]string{"block", "require approval"}[a]
+}
+
+// Limit is the most, in cents, that can be spent in a period.
+type Limit struct {
+	Period Period
+	Amount int64
+	Action Action
+}
+
+// Budget is a set of limits for a person or a team. CustomerIDs are the
+// favor customers whose spending counts against it; a Budget without any
+// counts everyone's, and applies to everyone.
+type Budget struct {
+	Name        string
+	CustomerIDs []string
+	Limits      []Limit
+}
+
+// appliesTo returns whether a customer's orders are covered by the budget.
+func (b Budget) appliesTo(customerID string) bool {
+	if len(b.CustomerIDs) == 0 {
+		return true
+	}
+	for _, id := range b.CustomerIDs {
+		if id == customerID {
+			return true
+		}
+	}
+	return false
+}
+
+// Overage is a limit a favor would go over.
+type Overage struct {
+	Budget Budget
+	Limit  Limit
+	// Spent is how much had already been spent this period, in cents.
+	Spent int64
+}
+
+func (o Overage) String() string {
+	return fmt.Sprintf("%v's %v limit of $%v ($%v spent so far)", o.Budget.Name, o.Limit.Period, favor.FormatCents(o.Limit.Amount), favor.FormatCents(o.Spent))
+}
+
+// Decision is what a policy thinks of placing a favor. Estimate is how much
+// it's expected to cost, in cents, and Overages are the limits it would go
+// over.
+type Decision struct {
+	Estimate int64
+	Overages []Overage
+}
+
+// Blocked returns whether any of the limits the favor would go over block it
+// outright.
+func (d Decision) Blocked() bool {
+	for _, o := range d.Overages {
+		if o.Limit.Action == Block {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsApproval returns whether the favor can only be placed with approval.
+func (d Decision) NeedsApproval() bool {
+	return !d.Blocked() && len(d.Overages) > 0
+}
+
+// coveredBy returns whether approving the other decision also approves this
+// one: it's expected to cost no more, and it doesn't go over any limit that
+// the other didn't, or by any more than the other did.
+func (d Decision) coveredBy(approved Decision) bool {
+	if d.Estimate > approved.Estimate {
+		return false
+	}
+	for _, o := range d.Overages {
+		covered := false
+		for _, a := range approved.Overages {
+			if a.Budget.Name == o.Budget.Name && a.Limit == o.Limit && a.Spent >= o.Spent {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// ExceededError is what PlaceFavor returns when it won't place a favor
+// because of a budget.
+type ExceededError struct {
+	Decision Decision
+	// Denied is whether approval was asked for and refused, rather than the
+	// favor being blocked outright.
+	Denied bool
+}
+
+func (e *ExceededError) Error() string {
+	overages := []string{}
+	for _, o := range e.Decision.Overages {
+		if e.Denied || o.Limit.Action == Block {
+			overages = append(overages, o.String())
+		}
+	}
+	reason := "would go over"
+	if e.Denied {
+		reason = "wasn't approved to go over"
+	}
+	return fmt.Sprintf("A favor estimated at $%v %v %v.", favor.FormatCents(e.Decision.Estimate), reason, strings.Join(overages, ", and "))
+}
@@ -0,0 +1,50 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeriodStart(t *testing.T) {
+	// a Wednesday afternoon
+	now := time.Date(2016, time.March, 9, 15, 4, 5, 0, time.UTC)
+	assert.Equal(t, time.Date(2016, time.March, 9, 0, 0, 0, 0, time.UTC), Daily.Start(now))
+	assert.Equal(t, time.Date(2016, time.March, 7, 0, 0, 0, 0, time.UTC), Weekly.Start(now))
+	assert.Equal(t, time.Date(2016, time.March, 1, 0, 0, 0, 0, time.UTC), Monthly.Start(now))
+
+	sunday := time.Date(2016, time.March, 13, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2016, time.March, 7, 0, 0, 0, 0, time.UTC), Weekly.Start(sunday))
+	monday := time.Date(2016, time.March, 14, 1, 0, 0, 0, time.UTC)
+	assert.Equal(t, monday.Truncate(24*time.Hour), Weekly.Start(monday))
+}
+
+func TestDecision(t *testing.T) {
+	team := Budget{Name: "the team"}
+	block := Overage{Budget: team, Limit: Limit{Period: Daily, Amount: 5000, Action: Block}, Spent: 4000}
+	approval := Overage{Budget: team, Limit: Limit{Period: Monthly, Amount: 50000, Action: RequireApproval}, Spent: 49000}
+
+	d := Decision{Estimate: 2000}
+	assert.False(t, d.Blocked())
+	assert.False(t, d.NeedsApproval())
+
+	d.Overages = []Overage{approval}
+	assert.False(t, d.Blocked())
+	assert.True(t, d.NeedsApproval())
+
+	d.Overages = []Overage{approval, block}
+	assert.True(t, d.Blocked())
+	assert.False(t, d.NeedsApproval())
+
+	err := &ExceededError{Decision: d}
+	assert.Equal(t, "A favor estimated at $20.00 would go over the team's daily limit of $50.00 ($40.00 spent so far).", err.Error())
+	err = &ExceededError{Decision: Decision{Estimate: 2000, Overages: []Overage{approval}}, Denied: true}
+	assert.Equal(t, "A favor estimated at $20.00 wasn't approved to go over the team's monthly limit of $500.00 ($490.00 spent so far).", err.Error())
+}
+
+func TestBudgetAppliesTo(t *testing.T) {
+	assert.True(t, Budget{}.appliesTo("10"))
+	assert.True(t, Budget{CustomerIDs: []string{"10", "11"}}.appliesTo("11"))
+	assert.False(t, Budget{CustomerIDs: []string{"10", "11"}}.appliesTo("12"))
+}
@@ -0,0 +1,321 @@
+package budget
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/store"
+)
+
+// Policy places favors with Client, as long as they fit the Budgets. Spending
+// is worked out from the receipts in Store, which something like store.Sync
+// should be keeping up to date. Favors the policy places itself are put in
+// the store straight away, and counted at their estimate until they have a
+// receipt, so that a bot ordering in a loop can't outrun the sync.
+//
+// Client, Store and Budgets are the only things that have to be set.
+type Policy struct {
+	Client  *favor.Client
+	Store   store.Store
+	Budgets []Budget
+
+	// CustomerID is who Client orders as, which decides which budgets apply.
+	// If it's empty, it's looked up with GetMe the first time it's needed.
+	CustomerID string
+
+	// Estimate works out how much a favor will cost, in cents. It defaults to
+	// HistoryEstimate.
+	Estimate func(rf favor.RequestFavor) (int64, error)
+	// DefaultEstimate is what HistoryEstimate guesses when there's no history
+	// to go on. Without it, a customer's first favor can't be estimated, and
+	// is refused rather than let through for free.
+	DefaultEstimate int64
+
+	// Approver is asked about favors that would go over a RequireApproval
+	// limit. Without one, they're refused like any other. It can take as long
+	// as it likes; other favors can still be checked and placed meanwhile.
+	Approver func(rf favor.RequestFavor, d Decision) (bool, error)
+
+	// Location is the time zone days, weeks and months start in. Defaults to
+	// the local one.
+	Location *time.Location
+	// Now defaults to time.Now, and is mostly here for tests.
+	Now func() time.Time
+
+	// lock is held from checking a favor to placing it, so two favors can't
+	// both squeeze under the same limit at once. It's let go while waiting on
+	// the Approver, though, since that could be a person taking their time.
+	lock    sync.Mutex
+	pending map[string]pendingFavor
+
+	// meLock only guards me, so that HistoryEstimate can be called with or
+	// without lock held.
+	meLock sync.Mutex
+	me     string
+}
+
+// pendingFavor is a favor the policy placed that doesn't have a receipt yet.
+type pendingFavor struct {
+	estimate int64
+	at       time.Time
+}
+
+func (p *Policy) now() time.Time {
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	loc := p.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return now().In(loc)
+}
+
+func (p *Policy) customerID() (string, error) {
+	if p.CustomerID != "" {
+		return p.CustomerID, nil
+	}
+	p.meLock.Lock()
+	defer p.meLock.Unlock()
+	if p.me == "" {
+		u, err := p.Client.GetMe()
+		if err != nil {
+			return "", fmt.Errorf("Error finding out who the budget policy is ordering as!:\n%v", err)
+		}
+		p.me = u.ID
+	}
+	return p.me, nil
+}
+
+func cancelled(f favor.Favor) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(f.Stage)), "cancel")
+}
+
+// spent returns how much has been spent against a budget since start, in
+// cents. Cancelled favors don't count.
+func (p *Policy) spent(b Budget, start time.Time) (int64, error) {
+	customers := b.CustomerIDs
+	if len(customers) == 0 {
+		customers = []string{""}
+	}
+
+	total := int64(0)
+	seen := map[string]bool{}
+	for _, customer := range customers {
+		records, err := p.Store.Query(store.Query{From: start, CustomerID: customer})
+		if err != nil {
+			return 0, err
+		}
+		for _, r := range records {
+			id := r.Favor.ID
+			if seen[id] || cancelled(r.Favor) {
+				seen[id] = true
+				continue
+			}
+			seen[id] = true
+
+			amounts, err := r.Favor.Receipt.Amounts()
+			if err == nil && amounts.Total() > 0 {
+				total += amounts.Total()
+				delete(p.pending, id)
+			} else if pf, ok := p.pending[id]; ok {
+				total += pf.estimate
+			}
+		}
+	}
+
+	// Favors this policy placed count against it even if the store doesn't
+	// have them, or has them without a time we can go by.
+	me, err := p.customerID()
+	if err != nil {
+		return 0, err
+	}
+	if b.appliesTo(me) {
+		for id, pf := range p.pending {
+			if !seen[id] && !pf.at.Before(start) {
+				total += pf.estimate
+			}
+		}
+	}
+	return total, nil
+}
+
+// HistoryEstimate guesses what a favor will cost from what the same customer
+// has paid before: the average total of their favors from the same merchant,
+// or of all their favors if they've never ordered from it, or DefaultEstimate
+// if they've never ordered anything. If DefaultEstimate isn't set either,
+// that's an error.
+func (p *Policy) HistoryEstimate(rf favor.RequestFavor) (int64, error) {
+	me, err := p.customerID()
+	if err != nil {
+		return 0, err
+	}
+	records, err := p.Store.Query(store.Query{CustomerID: me})
+	if err != nil {
+		return 0, err
+	}
+
+	var merchantTotal, merchantCount, allTotal, allCount int64
+	for _, r := range records {
+		amounts, err := r.Favor.Receipt.Amounts()
+		if err != nil || amounts.Total() == 0 || cancelled(r.Favor) {
+			continue
+		}
+		allTotal += amounts.Total()
+		allCount++
+		if rf.MerchantID != 0 && r.MerchantID() == strconv.Itoa(rf.MerchantID) {
+			merchantTotal += amounts.Total()
+			merchantCount++
+		}
+	}
+	switch {
+	case merchantCount > 0:
+		return merchantTotal / merchantCount, nil
+	case allCount > 0:
+		return allTotal / allCount, nil
+	}
+	if p.DefaultEstimate <= 0 {
+		return 0, fmt.Errorf("There's no history to estimate what the favor will cost from, and no DefaultEstimate to fall back on.")
+	}
+	return p.DefaultEstimate, nil
+}
+
+// prune forgets pending favors that were placed before the longest of the
+// limits started, since they can't count against anything anymore. Without
+// this, favors that never get a receipt would stick around forever. The lock
+// has to be held.
+func (p *Policy) prune(now time.Time) {
+	var earliest time.Time
+	for _, b := range p.Budgets {
+		for _, l := range b.Limits {
+			if start := l.Period.Start(now); earliest.IsZero() || start.Before(earliest) {
+				earliest = start
+			}
+		}
+	}
+	for id, pf := range p.pending {
+		if earliest.IsZero() || pf.at.Before(earliest) {
+			delete(p.pending, id)
+		}
+	}
+}
+
+// check works out the decision for a favor. The lock has to be held.
+func (p *Policy) check(rf favor.RequestFavor) (Decision, error) {
+	d := Decision{}
+	estimate := p.HistoryEstimate
+	if p.Estimate != nil {
+		estimate = p.Estimate
+	}
+	var err error
+	if d.Estimate, err = estimate(rf); err != nil {
+		return d, err
+	}
+
+	me, err := p.customerID()
+	if err != nil {
+		return d, err
+	}
+	now := p.now()
+	p.prune(now)
+	for _, b := range p.Budgets {
+		if !b.appliesTo(me) {
+			continue
+		}
+		for _, l := range b.Limits {
+			spent, err := p.spent(b, l.Period.Start(now))
+			if err != nil {
+				return d, err
+			}
+			if spent+d.Estimate > l.Amount {
+				d.Overages = append(d.Overages, Overage{Budget: b, Limit: l, Spent: spent})
+			}
+		}
+	}
+	return d, nil
+}
+
+// Check returns what the policy thinks of placing a favor, without placing
+// it.
+func (p *Policy) Check(rf favor.RequestFavor) (Decision, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.check(rf)
+}
+
+// approve asks the Approver about a favor, letting go of the lock while it
+// waits for an answer. The lock has to be held.
+func (p *Policy) approve(rf favor.RequestFavor, d Decision) (bool, error) {
+	p.lock.Unlock()
+	defer p.lock.Lock()
+	return p.Approver(rf, d)
+}
+
+// PlaceFavor places a favor, if the budgets allow it. Favors that would go
+// over a limit get an *ExceededError instead. If the favor is placed but
+// can't be saved to the store, it's returned along with the error, since it's
+// too late to take it back.
+func (p *Policy) PlaceFavor(rf favor.RequestFavor) (favor.Favor, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var d Decision
+	var approved *Decision
+	for {
+		var err error
+		if d, err = p.check(rf); err != nil {
+			return favor.Favor{}, err
+		}
+		if d.Blocked() {
+			return favor.Favor{}, &ExceededError{Decision: d}
+		}
+		if !d.NeedsApproval() || (approved != nil && d.coveredBy(*approved)) {
+			break
+		}
+		if p.Approver == nil {
+			return favor.Favor{}, &ExceededError{Decision: d, Denied: true}
+		}
+
+		// Other favors can be placed while we wait, so once it's approved,
+		// it's checked again, and only asked about again if those made it
+		// worse than what was approved.
+		ok, err := p.approve(rf, d)
+		if err != nil {
+			return favor.Favor{}, err
+		}
+		if !ok {
+			return favor.Favor{}, &ExceededError{Decision: d, Denied: true}
+		}
+		asked := d
+		approved = &asked
+	}
+
+	f, err := p.Client.PlaceFavor(rf)
+	if err != nil {
+		return favor.Favor{}, err
+	}
+
+	now := p.now()
+	if p.pending == nil {
+		p.pending = map[string]pendingFavor{}
+	}
+	id := f.ID
+	if id == "" {
+		// It still needs counting, even if we can't ever match it up with
+		// a receipt.
+		id = fmt.Sprintf("unknown-%d", now.UnixNano())
+	}
+	p.pending[id] = pendingFavor{estimate: d.Estimate, at: now}
+	if f.ID == "" {
+		return f, nil
+	}
+	if _, err := p.Store.Upsert(f, now); err != nil {
+		return f, fmt.Errorf("Favor %v was placed, but couldn't be saved to the store!:\n%v", f.ID, err)
+	}
+	return f, nil
+}
@@ -0,0 +1,271 @@
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/favortest"
+	"github.com/verygoodsoftwarenotvirus/favor/store"
+)
+
+// wednesday is when all of these tests happen.
+var wednesday = time.Date(2016, time.March, 9, 15, 0, 0, 0, time.UTC)
+
+// fakeAPI places favors for customer 10, and counts how many it's placed.
+type fakeAPI struct {
+	lock   sync.Mutex
+	placed int
+	meHits int
+}
+
+func setupPolicy(t *testing.T, api *fakeAPI) *Policy {
+	c := favortest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.lock.Lock()
+		defer api.lock.Unlock()
+		switch r.URL.Path {
+		case "/api/v5/me":
+			api.meHits++
+			fmt.Fprint(w, `{"user": {"id": "10"}}`)
+		case "/api/v5/favors/":
+			api.placed++
+			fmt.Fprintf(w, `{"favor": {"id": "new-%d", "stage": "pending", "merchant_id": "2", "customer": {"id": "10"}, "created_at": %d}}`, api.placed, wednesday.Unix())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	return &Policy{
+		Client:   c,
+		Store:    store.NewMemoryStore(),
+		Location: time.UTC,
+		Now:      func() time.Time { return wednesday },
+	}
+}
+
+// greg is customer 10, who all of these tests are about, and everyone else is
+// customer 11.
+var (
+	greg        = favor.User{ID: "10"}
+	someoneElse = favor.User{ID: "11"}
+)
+
+func TestHistoryEstimate(t *testing.T) {
+	p := setupPolicy(t, &fakeAPI{})
+
+	_, err := p.HistoryEstimate(favor.RequestFavor{MerchantID: 2})
+	assert.NotNil(t, err, "with no history and no default, there's nothing to go on")
+
+	p.DefaultEstimate = 2500
+	estimate, err := p.HistoryEstimate(favor.RequestFavor{MerchantID: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2500), estimate, "with no history, the default should be used")
+
+	for _, f := range favortest.Favors(
+		favortest.Spec{ID: "1", Stage: "delivered", Customer: greg, MerchantID: "2", Created: wednesday, Price: "10.00", Tip: "2.00"},
+		favortest.Spec{ID: "2", Stage: "delivered", Customer: greg, MerchantID: "2", Created: wednesday, Price: "20.00", Tip: "2.00"},
+		favortest.Spec{ID: "3", Stage: "delivered", Customer: greg, MerchantID: "3", Created: wednesday, Price: "40.00", Tip: "2.00"},
+		favortest.Spec{ID: "4", Stage: "delivered", Customer: someoneElse, MerchantID: "2", Created: wednesday, Price: "100.00", Tip: "2.00"},
+	) {
+		_, err := p.Store.Upsert(f, wednesday)
+		assert.Nil(t, err)
+	}
+
+	estimate, err = p.HistoryEstimate(favor.RequestFavor{MerchantID: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2250), estimate)
+
+	estimate, err = p.HistoryEstimate(favor.RequestFavor{MerchantID: 99})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3083), estimate)
+}
+
+func TestPolicyPlaceFavor(t *testing.T) {
+	api := &fakeAPI{}
+	p := setupPolicy(t, api)
+	p.Budgets = []Budget{
+		{Name: "Greg", CustomerIDs: []string{"10"}, Limits: []Limit{{Period: Daily, Amount: 5000, Action: Block}}},
+		{Name: "someone else", CustomerIDs: []string{"11"}, Limits: []Limit{{Period: Daily, Amount: 0, Action: Block}}},
+	}
+	p.Estimate = func(rf favor.RequestFavor) (int64, error) { return 2000, nil }
+
+	// yesterday's spending and other people's don't count
+	for _, f := range favortest.Favors(
+		favortest.Spec{ID: "1", Stage: "delivered", Customer: greg, MerchantID: "2", Created: wednesday.Add(-24 * time.Hour), Price: "100.00", Tip: "2.00"},
+		favortest.Spec{ID: "2", Stage: "delivered", Customer: someoneElse, MerchantID: "2", Created: wednesday, Price: "100.00", Tip: "2.00"},
+		favortest.Spec{ID: "3", Stage: "delivered", Customer: greg, MerchantID: "2", Created: wednesday, Price: "2.50", Tip: "2.00"},
+	) {
+		_, err := p.Store.Upsert(f, wednesday)
+		assert.Nil(t, err)
+	}
+
+	d, err := p.Check(favor.RequestFavor{})
+	assert.Nil(t, err)
+	assert.Equal(t, Decision{Estimate: 2000}, d)
+
+	f, err := p.PlaceFavor(favor.RequestFavor{})
+	assert.Nil(t, err)
+	assert.Equal(t, "new-1", f.ID)
+	r, ok, err := p.Store.Get("new-1")
+	assert.Nil(t, err)
+	assert.True(t, ok, "placed favors should be saved to the store")
+	assert.Equal(t, "pending", r.Favor.Stage)
+
+	// $10 earlier, plus $20 estimated for the first favor, and $20 more is
+	// exactly $50, which is allowed
+	_, err = p.PlaceFavor(favor.RequestFavor{})
+	assert.Nil(t, err)
+
+	_, err = p.PlaceFavor(favor.RequestFavor{})
+	exceeded := &ExceededError{}
+	assert.True(t, errors.As(err, &exceeded))
+	assert.False(t, exceeded.Denied)
+	assert.Equal(t, int64(5000), exceeded.Decision.Overages[0].Spent)
+	assert.Equal(t, 2, api.placed, "blocked favors shouldn't be placed")
+	assert.Equal(t, 1, api.meHits, "who we are should only be looked up once")
+
+	// once a receipt shows up, it's counted instead of the estimate
+	paid := favortest.Spec{ID: "new-1", Stage: "delivered", Customer: greg, MerchantID: "2", Created: wednesday}.Favor()
+	paid.Receipt = favor.Receipt{Price: "1.00"}
+	_, err = p.Store.Upsert(paid, wednesday)
+	assert.Nil(t, err)
+	d, err = p.Check(favor.RequestFavor{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3100), d.Overages[0].Spent)
+
+	// and cancelled favors don't count at all
+	paid.Stage = "cancelled"
+	paid.Receipt = favor.Receipt{Price: "49.00"}
+	_, err = p.Store.Upsert(paid, wednesday)
+	assert.Nil(t, err)
+	d, err = p.Check(favor.RequestFavor{})
+	assert.Nil(t, err)
+	assert.Empty(t, d.Overages)
+}
+
+func TestPolicyApproval(t *testing.T) {
+	api := &fakeAPI{}
+	p := setupPolicy(t, api)
+	p.CustomerID = "10"
+	p.Budgets = []Budget{
+		{Name: "the team", Limits: []Limit{
+			{Period: Weekly, Amount: 1000, Action: RequireApproval},
+			{Period: Monthly, Amount: 100000, Action: Block},
+		}},
+	}
+	p.Estimate = func(rf favor.RequestFavor) (int64, error) { return 2000, nil }
+
+	_, err := p.PlaceFavor(favor.RequestFavor{})
+	exceeded := &ExceededError{}
+	assert.True(t, errors.As(err, &exceeded))
+	assert.True(t, exceeded.Denied, "without an approver, nothing should be approved")
+
+	asked := []Decision{}
+	approve := false
+	p.Approver = func(rf favor.RequestFavor, d Decision) (bool, error) {
+		asked = append(asked, d)
+		return approve, nil
+	}
+	_, err = p.PlaceFavor(favor.RequestFavor{})
+	assert.True(t, errors.As(err, &exceeded))
+	assert.True(t, exceeded.Denied)
+
+	approve = true
+	f, err := p.PlaceFavor(favor.RequestFavor{})
+	assert.Nil(t, err)
+	assert.Equal(t, "new-1", f.ID)
+	assert.Len(t, asked, 2)
+	assert.Equal(t, Weekly, asked[0].Overages[0].Limit.Period)
+	assert.Equal(t, 1, api.placed)
+	assert.Equal(t, 0, api.meHits, "CustomerID was set, so there's no need to look it up")
+
+	p.Approver = func(rf favor.RequestFavor, d Decision) (bool, error) {
+		return false, errors.New("the approver is out to lunch")
+	}
+	_, err = p.PlaceFavor(favor.RequestFavor{})
+	assert.EqualError(t, err, "the approver is out to lunch")
+}
+
+func TestPolicyApprovalDoesNotHoldTheLock(t *testing.T) {
+	api := &fakeAPI{}
+	p := setupPolicy(t, api)
+	p.CustomerID = "10"
+	p.Budgets = []Budget{
+		{Name: "the team", Limits: []Limit{{Period: Weekly, Amount: 1000, Action: RequireApproval}}},
+	}
+	p.Estimate = func(rf favor.RequestFavor) (int64, error) { return 2000, nil }
+
+	// while the first favor waits on approval, someone else gets one placed
+	asked := []Decision{}
+	p.Approver = func(rf favor.RequestFavor, d Decision) (bool, error) {
+		asked = append(asked, d)
+		if len(asked) == 1 {
+			_, err := p.PlaceFavor(favor.RequestFavor{})
+			assert.Nil(t, err)
+		}
+		return true, nil
+	}
+	_, err := p.PlaceFavor(favor.RequestFavor{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, api.placed)
+	if assert.Len(t, asked, 3, "the first favor should be asked about again, since the second made it worse") {
+		assert.Equal(t, int64(0), asked[0].Overages[0].Spent)
+		assert.Equal(t, int64(2000), asked[2].Overages[0].Spent)
+	}
+}
+
+func TestPolicyHistoryEstimateIsSafeToCallAnytime(t *testing.T) {
+	api := &fakeAPI{}
+	p := setupPolicy(t, api)
+	p.DefaultEstimate = 1000
+	p.Budgets = []Budget{{Name: "Greg", CustomerIDs: []string{"10"}, Limits: []Limit{{Period: Daily, Amount: 5000, Action: Block}}}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := p.HistoryEstimate(favor.RequestFavor{})
+			assert.Nil(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := p.Check(favor.RequestFavor{})
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, api.meHits, "who we are should only be looked up once")
+}
+
+func TestPolicyForgetsOldPendingFavors(t *testing.T) {
+	p := setupPolicy(t, &fakeAPI{})
+	p.Budgets = []Budget{
+		{Name: "Greg", CustomerIDs: []string{"10"}, Limits: []Limit{
+			{Period: Daily, Amount: 5000, Action: Block},
+			{Period: Weekly, Amount: 10000, Action: Block},
+		}},
+	}
+	p.Estimate = func(rf favor.RequestFavor) (int64, error) { return 2000, nil }
+
+	_, err := p.PlaceFavor(favor.RequestFavor{})
+	assert.Nil(t, err)
+	assert.Len(t, p.pending, 1)
+
+	// the next day, it still counts against the week
+	p.Now = func() time.Time { return wednesday.Add(24 * time.Hour) }
+	_, err = p.Check(favor.RequestFavor{})
+	assert.Nil(t, err)
+	assert.Len(t, p.pending, 1)
+
+	// but it never got a receipt, and by next week it can't count against anything
+	p.Now = func() time.Time { return wednesday.Add(7 * 24 * time.Hour) }
+	_, err = p.Check(favor.RequestFavor{})
+	assert.Nil(t, err)
+	assert.Empty(t, p.pending)
+}
@@ -0,0 +1,276 @@
+package favor
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Names of the endpoints that can be cached, for use as keys in CacheConfig.TTLs
+const (
+	CacheMerchant  = "merchant"
+	CacheMerchants = "merchants"
+)
+
+// CacheEntry is a response body saved for later, along with whatever the
+// server told us we'd need to ask whether it's changed.
+type CacheEntry struct {
+	Body         []byte    `json:"body"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Expires      time.Time `json:"expires"`
+}
+
+// Cache is anything that can hold on to CacheEntries.
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry) error
+	Delete(key string) error
+	Clear() error
+}
+
+// CacheConfig tells a Client where to cache responses, and for how long, per
+// endpoint. Endpoints without a TTL aren't cached at all.
+type CacheConfig struct {
+	Store Cache
+	TTLs  map[string]time.Duration
+}
+
+// MemoryCache is a Cache that keeps up to Capacity entries in memory, and
+// throws out whichever was used least recently when it runs out of room.
+type MemoryCache struct {
+	capacity int
+	lock     sync.Mutex
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// NewMemoryCache is a constructor function returning an empty MemoryCache.
+func NewMemoryCache(capacity int) *MemoryCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &MemoryCache{capacity: capacity, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+// Get returns the entry for a key, if there is one.
+func (m *MemoryCache) Get(key string) (CacheEntry, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	m.order.MoveToFront(e)
+	return e.Value.(*memoryCacheItem).entry, true
+}
+
+// Set saves an entry, evicting the least recently used entry if the cache is full.
+func (m *MemoryCache) Set(key string, entry CacheEntry) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if e, ok := m.entries[key]; ok {
+		e.Value.(*memoryCacheItem).entry = entry
+		m.order.MoveToFront(e)
+		return nil
+	}
+	m.entries[key] = m.order.PushFront(&memoryCacheItem{key: key, entry: entry})
+	for m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+	return nil
+}
+
+// Delete removes the entry for a key.
+func (m *MemoryCache) Delete(key string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if e, ok := m.entries[key]; ok {
+		m.order.Remove(e)
+		delete(m.entries, key)
+	}
+	return nil
+}
+
+// Clear removes every entry.
+func (m *MemoryCache) Clear() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.order.Init()
+	m.entries = map[string]*list.Element{}
+	return nil
+}
+
+// Len returns how many entries are in the cache.
+func (m *MemoryCache) Len() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.order.Len()
+}
+
+// FileCache is a Cache that keeps each entry in its own file in Dir, so that
+// it survives restarts.
+type FileCache struct {
+	Dir string
+}
+
+const fileCacheExtension = ".cache.json"
+
+func (f FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(f.Dir, hex.EncodeToString(sum[:])+fileCacheExtension)
+}
+
+// Get returns the entry for a key, if there is one. Entries that can't be read
+// are treated as missing.
+func (f FileCache) Get(key string) (CacheEntry, bool) {
+	contents, err := ioutil.ReadFile(f.path(key))
+	if err != nil {
+		return CacheEntry{}, false
+	}
+	entry := CacheEntry{}
+	if err := json.Unmarshal(contents, &entry); err != nil {
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+// Set saves an entry to disk.
+func (f FileCache) Set(key string, entry CacheEntry) error {
+	if err := os.MkdirAll(f.Dir, 0700); err != nil {
+		return err
+	}
+	contents, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(f.path(key), contents, 0600)
+}
+
+// Delete removes the entry for a key from disk.
+func (f FileCache) Delete(key string) error {
+	err := os.Remove(f.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Clear removes every entry from disk, leaving anything else in Dir alone.
+func (f FileCache) Clear() error {
+	files, err := ioutil.ReadDir(f.Dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), fileCacheExtension) {
+			if err := os.Remove(filepath.Join(f.Dir, file.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Unexported function used to send off GET requests whose responses can be
+// cached. Fresh entries are returned without asking the server at all, and
+// stale entries are revalidated with the server if it gave us an ETag or a
+// Last-Modified date the first time around.
+func (c Client) makeCachedAPIRequest(endpoint string, uri string) ([]byte, error) {
+	if c.Cache == nil || c.Cache.Store == nil || c.Cache.TTLs[endpoint] <= 0 {
+		return c.makeAPIRequest("get", uri)
+	}
+	ttl := c.Cache.TTLs[endpoint]
+
+	cached, found := c.Cache.Store.Get(uri)
+	if found && time.Now().Before(cached.Expires) {
+		return cached.Body, nil
+	}
+
+	_, res, err := c.do(func() (*http.Request, error) {
+		req, err := http.NewRequest("GET", uri, nil)
+		if err != nil {
+			return nil, err
+		}
+		if found && cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if found && cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if found && res.StatusCode == http.StatusNotModified {
+		cached.Expires = time.Now().Add(ttl)
+		// Failing to save the entry just means we'll ask again next time
+		c.Cache.Store.Set(uri, cached)
+		return cached.Body, nil
+	}
+
+	responseBody, err := readResponse(res)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusOK {
+		c.Cache.Store.Set(uri, CacheEntry{
+			Body:         responseBody,
+			ETag:         res.Header.Get("ETag"),
+			LastModified: res.Header.Get("Last-Modified"),
+			Expires:      time.Now().Add(ttl),
+		})
+	}
+	return responseBody, nil
+}
+
+// InvalidateMerchant removes a merchant from the cache, so the next call to
+// GetMerchant asks the server.
+func (c Client) InvalidateMerchant(id string) error {
+	if c.Cache == nil || c.Cache.Store == nil {
+		return nil
+	}
+	return c.Cache.Store.Delete(c.merchantURL(id))
+}
+
+// InvalidateMerchants removes the merchants near a point from the cache, so the
+// next call to GetMerchants for that point asks the server.
+func (c Client) InvalidateMerchants(lat, long float64) error {
+	if c.Cache == nil || c.Cache.Store == nil {
+		return nil
+	}
+	return c.Cache.Store.Delete(c.merchantsURL(lat, long))
+}
+
+// ClearCache removes everything from the cache.
+func (c Client) ClearCache() error {
+	if c.Cache == nil || c.Cache.Store == nil {
+		return nil
+	}
+	return c.Cache.Store.Clear()
+}
@@ -0,0 +1,96 @@
+package favor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCacheEviction(t *testing.T) {
+	m := NewMemoryCache(2)
+	m.Set("a", CacheEntry{Body: []byte("a")})
+	m.Set("b", CacheEntry{Body: []byte("b")})
+	m.Get("a")
+	m.Set("c", CacheEntry{Body: []byte("c")})
+
+	_, found := m.Get("b")
+	assert.False(t, found, "The least recently used entry should have been evicted")
+	_, found = m.Get("a")
+	assert.True(t, found)
+	assert.Equal(t, 2, m.Len())
+
+	m.Delete("a")
+	_, found = m.Get("a")
+	assert.False(t, found)
+
+	m.Clear()
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestFileCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "favor")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	f := FileCache{Dir: dir}
+	entry := CacheEntry{Body: []byte(`{"lol": "yup"}`), ETag: `"abc"`, Expires: time.Now().Add(time.Hour).Round(0)}
+	assert.Nil(t, f.Set("https://api.askfavor.com/api/v5/merchant/1", entry))
+
+	actual, found := f.Get("https://api.askfavor.com/api/v5/merchant/1")
+	assert.True(t, found)
+	assert.Equal(t, entry.Body, actual.Body)
+	assert.Equal(t, entry.ETag, actual.ETag)
+	assert.True(t, entry.Expires.Equal(actual.Expires))
+
+	assert.Nil(t, f.Clear())
+	_, found = f.Get("https://api.askfavor.com/api/v5/merchant/1")
+	assert.False(t, found)
+	assert.Nil(t, f.Delete("https://api.askfavor.com/api/v5/merchant/1"), "Deleting missing entries shouldn't fail")
+}
+
+func TestCachedGetMerchant(t *testing.T) {
+	var requests, revalidations int32
+
+	s := setupMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&revalidations, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprintln(w, `{"merchant": {"id": "1234", "name": "Farts McGregor's Corntopia"}}`)
+	}))
+	store := NewMemoryCache(10)
+	s.Cache = &CacheConfig{Store: store, TTLs: map[string]time.Duration{CacheMerchant: time.Hour}}
+
+	for i := 0; i < 3; i++ {
+		m, err := s.GetMerchant("1234")
+		assert.Nil(t, err)
+		assert.Equal(t, "Farts McGregor's Corntopia", m.Name)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "Fresh entries shouldn't hit the server")
+
+	// make the entry stale, so it has to be revalidated
+	entry, _ := store.Get(s.merchantURL("1234"))
+	entry.Expires = time.Now().Add(-time.Minute)
+	store.Set(s.merchantURL("1234"), entry)
+
+	m, err := s.GetMerchant("1234")
+	assert.Nil(t, err)
+	assert.Equal(t, "Farts McGregor's Corntopia", m.Name)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&revalidations))
+
+	assert.Nil(t, s.InvalidateMerchant("1234"))
+	_, err = s.GetMerchant("1234")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&revalidations), "Invalidated entries should be fetched from scratch")
+}
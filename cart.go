@@ -0,0 +1,178 @@
+package favor
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CartItem represents a single line in a Cart. MealID and CategoryID are
+// optional, and only need to be set when the item came from a Merchant's
+// Menu. Price is the price of a single item, modifiers included.
+type CartItem struct {
+	Name       string
+	Quantity   int
+	Modifiers  []string
+	Notes      string
+	Price      float64
+	MealID     int
+	CategoryID int
+}
+
+// CartItemFromMeal builds a CartItem from a Meal on a Menu, with the price
+// of any selected options added to the price of the meal.
+func CartItemFromMeal(meal Meal, quantity int, options ...MenuOption) (CartItem, error) {
+	item := CartItem{
+		Name:     meal.Name,
+		Quantity: quantity,
+	}
+
+	var err error
+	if item.MealID, err = strconv.Atoi(meal.ID); err != nil {
+		return CartItem{}, fmt.Errorf("Error parsing meal ID %v into integer!:\n%v", meal.ID, err)
+	}
+	if item.CategoryID, err = strconv.Atoi(meal.CategoryID); err != nil {
+		return CartItem{}, fmt.Errorf("Error parsing category ID %v into integer!:\n%v", meal.CategoryID, err)
+	}
+	if meal.Price != "" {
+		if item.Price, err = strconv.ParseFloat(meal.Price, 64); err != nil {
+			return CartItem{}, fmt.Errorf("Error parsing meal price %v into float!:\n%v", meal.Price, err)
+		}
+	}
+
+	for _, o := range options {
+		item.Modifiers = append(item.Modifiers, o.Name)
+		if o.Price == "" {
+			continue
+		}
+		price, err := strconv.ParseFloat(o.Price, 64)
+		if err != nil {
+			return CartItem{}, fmt.Errorf("Error parsing option price %v into float!:\n%v", o.Price, err)
+		}
+		item.Price += price
+	}
+	return item, nil
+}
+
+// normalize returns a copy of the item with whitespace trimmed, modifiers
+// sorted, and a quantity of at least one, so that equivalent items render
+// the same way regardless of who typed them in.
+func (ci CartItem) normalize() CartItem {
+	n := ci
+	n.Name = strings.TrimSpace(ci.Name)
+	n.Notes = strings.TrimSpace(ci.Notes)
+	if n.Quantity < 1 {
+		n.Quantity = 1
+	}
+	n.Modifiers = []string{}
+	for _, m := range ci.Modifiers {
+		if m = strings.TrimSpace(m); m != "" {
+			n.Modifiers = append(n.Modifiers, m)
+		}
+	}
+	sort.Strings(n.Modifiers)
+	return n
+}
+
+// sameAs returns whether two normalized items describe the same thing, and
+// could be combined into one line by adding their quantities.
+func (ci CartItem) sameAs(other CartItem) bool {
+	if !strings.EqualFold(ci.Name, other.Name) || ci.Notes != other.Notes || ci.MealID != other.MealID || ci.Price != other.Price {
+		return false
+	}
+	return strings.Join(ci.Modifiers, "\x00") == strings.Join(other.Modifiers, "\x00")
+}
+
+// String renders the item the way it should appear in a RequestFavor's
+// Wants field, for example:
+//
+//	2x Trailer Park (extra queso, no onions) - cut in half please
+func (ci CartItem) String() string {
+	n := ci.normalize()
+	s := fmt.Sprintf("%dx %v", n.Quantity, n.Name)
+	if len(n.Modifiers) > 0 {
+		s = fmt.Sprintf("%v (%v)", s, strings.Join(n.Modifiers, ", "))
+	}
+	if n.Notes != "" {
+		s = fmt.Sprintf("%v - %v", s, n.Notes)
+	}
+	return s
+}
+
+// Cart is a collection of items that will eventually become the Wants text of
+// a RequestFavor. It exists so that orders come out consistently formatted no
+// matter who puts them together.
+type Cart struct {
+	Items []CartItem
+}
+
+// Add puts an item into the cart. If an identical item is already in the cart,
+// the quantities are combined instead of adding a new line.
+func (c *Cart) Add(item CartItem) {
+	n := item.normalize()
+	for i, existing := range c.Items {
+		if existing.sameAs(n) {
+			c.Items[i].Quantity += n.Quantity
+			return
+		}
+	}
+	c.Items = append(c.Items, n)
+}
+
+// Wants renders the canonical Wants string for the cart, one item per line.
+func (c Cart) Wants() string {
+	lines := []string{}
+	for _, item := range c.Items {
+		lines = append(lines, item.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Subtotal returns the estimated cost of everything in the cart, before tip,
+// delivery charges, and any other fees Favor decides to tack on. Items without
+// a known price are treated as free, so this should be taken with a grain of salt.
+func (c Cart) Subtotal() float64 {
+	var total float64
+	for _, item := range c.Items {
+		n := item.normalize()
+		total += n.Price * float64(n.Quantity)
+	}
+	return total
+}
+
+// RequestFavor converts the cart into a RequestFavor for the given merchant,
+// to be delivered to the given address. If any item in the cart came from a
+// menu, the first such item's IDs are used for the meal fields.
+func (c Cart) RequestFavor(m Merchant, a Address) (RequestFavor, error) {
+	if len(c.Items) == 0 {
+		return RequestFavor{}, fmt.Errorf("Cannot build a favor request from an empty cart.")
+	}
+
+	merchantID, err := strconv.Atoi(m.ID)
+	if err != nil {
+		return RequestFavor{}, fmt.Errorf("Error parsing merchant ID %v into integer!:\n%v", m.ID, err)
+	}
+	marketID, _ := strconv.Atoi(m.MarketID)
+
+	rf := RequestFavor{
+		Title:      m.Name,
+		Wants:      c.Wants(),
+		MarketID:   marketID,
+		MerchantID: merchantID,
+	}
+	rf, err = rf.FromAddress(a)
+	if err != nil {
+		return RequestFavor{}, err
+	}
+
+	for _, item := range c.Items {
+		if item.MealID != 0 {
+			rf.MealID = item.MealID
+			rf.OriginMealID = item.MealID
+			rf.OriginCategoryID = item.CategoryID
+			break
+		}
+	}
+	return rf, nil
+}
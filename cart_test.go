@@ -0,0 +1,75 @@
+package favor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCartWants(t *testing.T) {
+	c := Cart{}
+	c.Add(CartItem{Name: "Trailer Park", Quantity: 1, Modifiers: []string{"no onions", "extra queso"}, Price: 4.75})
+	c.Add(CartItem{Name: " Trailer Park ", Quantity: 1, Modifiers: []string{"extra queso", "no onions"}, Price: 4.75})
+	c.Add(CartItem{Name: "Queso", Notes: "  the big one  ", Price: 6})
+
+	expected := "2x Trailer Park (extra queso, no onions)\n1x Queso - the big one"
+	assert.Equal(t, expected, c.Wants())
+	assert.InDelta(t, 15.5, c.Subtotal(), 0.0001)
+}
+
+func TestCartItemFromMeal(t *testing.T) {
+	meal := Meal{ID: "345", CategoryID: "12", Name: "Trailer Park", Price: "4.75"}
+	item, err := CartItemFromMeal(meal, 2, MenuOption{ID: "6", Name: "Get it trashy", Price: "0.50"})
+	assert.Nil(t, err)
+
+	expected := CartItem{
+		Name:       "Trailer Park",
+		Quantity:   2,
+		Modifiers:  []string{"Get it trashy"},
+		Price:      5.25,
+		MealID:     345,
+		CategoryID: 12,
+	}
+	assert.Equal(t, expected, item)
+
+	_, err = CartItemFromMeal(Meal{ID: "lol", CategoryID: "12"}, 1)
+	assert.NotNil(t, err)
+}
+
+func TestCartRequestFavor(t *testing.T) {
+	c := Cart{}
+	c.Add(CartItem{Name: "Napkins"})
+	c.Add(CartItem{Name: "Trailer Park", MealID: 345, CategoryID: 12})
+
+	m := Merchant{ID: "1234", MarketID: "1", Name: "Farts McGregor's Corntopia"}
+	a := Address{
+		Lat:       "-33.865143",
+		Lng:       "151.209900",
+		Street:    "42 Wallaby Way",
+		Zipcode:   "2000",
+		Apartment: "123",
+		Notes:     "In west Philadelphia, born and raised.",
+	}
+
+	expected := RequestFavor{
+		Title:            "Farts McGregor's Corntopia",
+		Wants:            "1x Napkins\n1x Trailer Park",
+		Lat:              -33.865143,
+		Lng:              151.2099,
+		Street:           "42 Wallaby Way",
+		Zipcode:          "2000",
+		MarketID:         1,
+		Apt:              "123",
+		Notes:            "In west Philadelphia, born and raised.",
+		MerchantID:       1234,
+		MealID:           345,
+		OriginMealID:     345,
+		OriginCategoryID: 12,
+	}
+	actual, err := c.RequestFavor(m, a)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, actual)
+
+	_, err = Cart{}.RequestFavor(m, a)
+	assert.NotNil(t, err)
+}
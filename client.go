@@ -8,23 +8,50 @@ import (
 	"strings"
 )
 
-// Client is our basic struct for making Favor API requests
+// Client is our basic struct for making Favor API requests. If TokenSource
+// is set, it's consulted for every request and Token is ignored. If Geofence
+// is set, favor requests are checked against it before they're placed. If
+// Cache is set, responses from the endpoints it has TTLs for are cached.
 type Client struct {
-	Token  string
-	Client http.Client
-	Secure bool
+	Token       string
+	TokenSource TokenSource
+	Client      http.Client
+	Secure      bool
+	Geofence    *Geofence
+	Cache       *CacheConfig
 }
 
-// New is a constructor function returning a new instance of a Favor Client.
-func New(token string) (*Client, error) {
+func validateToken(token string) error {
 	// As far as I can tell, in my limited research, the Favor token needs to be 32 digits long
 	if len(token) < 32 {
-		return nil, fmt.Errorf("The token provided is the incorrect length, a normal favorToken is 32 characters long.")
+		return fmt.Errorf("The token provided is the incorrect length, a normal favorToken is 32 characters long.")
+	}
+	return nil
+}
+
+// New is a constructor function returning a new instance of a Favor Client.
+func New(token string) (*Client, error) {
+	if err := validateToken(token); err != nil {
+		return nil, err
 	}
 	c := &Client{Token: token, Secure: true}
 	return c, nil
 }
 
+// NewWithTokenSource is a constructor function returning a new instance of a
+// Favor Client that asks ts for a token every time it makes a request.
+func NewWithTokenSource(ts TokenSource) (*Client, error) {
+	token, err := ts.Token()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateToken(token); err != nil {
+		return nil, err
+	}
+	c := &Client{TokenSource: ts, Secure: true}
+	return c, nil
+}
+
 // BuildURL constructs our Favor API URL when provided with an endpoint to hit and
 // any necessary query params. Example usages would be:
 // favor.BuildURL("hello", map[string]string{})
@@ -53,65 +80,110 @@ func (c Client) BuildURL(endpoint string, params map[string]string) string {
 	return returnURL
 }
 
-// Unexported function used to actually send requests off.
-func (c Client) makeAPIRequest(method string, url string) ([]byte, error) {
-	req, err := http.NewRequest(strings.ToUpper(method), url, nil)
-	if err != nil {
-		err = fmt.Errorf("API request failed to build and returned this error:\n %v", err)
-		return nil, err
+// token returns the token to send along with the next request, preferring
+// the TokenSource if one has been provided.
+func (c Client) token() (string, error) {
+	if c.TokenSource != nil {
+		token, err := c.TokenSource.Token()
+		if err != nil {
+			return "", fmt.Errorf("Retrieving a token failed and returned this error:\n %v", err)
+		}
+		return token, nil
 	}
+	return c.Token, nil
+}
 
-	req.Header.Add("favorToken", c.Token)
-
-	res, err := c.Client.Do(req)
-	if err != nil {
-		err = fmt.Errorf("API request failed to complete and returned this error:\n %v", err)
-		return nil, err
+// Unexported function that sends off whatever request build returns. If the server
+// rejects our token and the TokenSource knows how to get a new one, the request is
+// rebuilt and sent again, but only once, because we're not animals.
+func (c Client) do(build func() (*http.Request, error)) (*http.Request, *http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := build()
+		if err != nil {
+			err = fmt.Errorf("API request failed to build and returned this error:\n %v", err)
+			return nil, nil, err
+		}
+
+		token, err := c.token()
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Add("favorToken", token)
+
+		res, err := c.Client.Do(req)
+		if err != nil {
+			err = fmt.Errorf("API request failed to complete and returned this error:\n %v", err)
+			return nil, nil, err
+		}
+		if res.StatusCode != http.StatusUnauthorized {
+			return req, res, nil
+		}
+		res.Body.Close()
+
+		refresher, ok := c.TokenSource.(Refresher)
+		if !ok || attempt > 0 {
+			return nil, nil, &StatusError{StatusCode: res.StatusCode, Body: "the token is unauthorized"}
+		}
+		if err := refresher.Refresh(); err != nil {
+			return nil, nil, fmt.Errorf("API request was unauthorized, and refreshing the token returned this error:\n %v", err)
+		}
 	}
-	defer res.Body.Close()
+}
+
+// StatusError is returned when the Favor API answers with anything other than
+// a success, so that callers can tell a favor that doesn't exist apart from
+// the server having a bad day. Body is whatever the server said about it.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API request failed with status %v: %v", e.StatusCode, e.Body)
+}
 
+// readResponse reads the whole response body, and turns anything that isn't
+// a success into a StatusError.
+func readResponse(res *http.Response) ([]byte, error) {
 	responseBody, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		err = fmt.Errorf("API response failed to close and returned this error:\n %v", err)
 		return nil, err
 	}
-
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, &StatusError{StatusCode: res.StatusCode, Body: strings.TrimSpace(string(responseBody))}
+	}
 	return responseBody, nil
 }
 
 // Unexported function used to actually send requests off.
-func (c Client) makeAPIRequestWithBody(method string, url string, body url.Values) ([]byte, error) {
-	b := strings.NewReader(body.Encode())
-	req, err := http.NewRequest(strings.ToUpper(method), url, b)
-	if err != nil {
-		err = fmt.Errorf("API request failed to build and returned this error:\n %v", err)
-		return nil, err
-	}
-
-	req.Header.Add("favorToken", c.Token)
-
-	res, err := c.Client.Do(req)
+func (c Client) makeAPIRequest(method string, url string) ([]byte, error) {
+	_, res, err := c.do(func() (*http.Request, error) {
+		return http.NewRequest(strings.ToUpper(method), url, nil)
+	})
 	if err != nil {
-		err = fmt.Errorf("API request failed to complete and returned this error:\n %v", err)
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	_ = req.ParseForm()
-	// x, _ := ioutil.ReadAll(req.Body)
-	fmt.Printf("req.Method   : %v\n", req.Method)
-	fmt.Printf("req.URL      : %v\n", req.URL)
-	fmt.Printf("req.Header   : %v\n", req.Header)
-	fmt.Printf("req.Host     : %v\n", req.Host)
-	fmt.Printf("req.Form     : %v\n", req.Form)
-	fmt.Printf("req.PostForm : %v\n", req.PostForm)
+	return readResponse(res)
+}
 
-	responseBody, err := ioutil.ReadAll(res.Body)
+// Unexported function used to actually send requests off.
+func (c Client) makeAPIRequestWithBody(method string, url string, body url.Values) ([]byte, error) {
+	_, res, err := c.do(func() (*http.Request, error) {
+		req, err := http.NewRequest(strings.ToUpper(method), url, strings.NewReader(body.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		// Without this, servers won't bother reading the form at all.
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
 	if err != nil {
-		err = fmt.Errorf("API response failed to close and returned this error:\n %v", err)
 		return nil, err
 	}
-	fmt.Printf("responseBody : %v\n", string(responseBody))
+	defer res.Body.Close()
 
-	return responseBody, nil
+	return readResponse(res)
 }
@@ -1,10 +1,13 @@
 package favor
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"testing"
 )
 
@@ -14,7 +17,9 @@ func init() {
 	dummyToken = "thisisarandomstringfortestinglol"
 }
 
-func setupMockClient(response string) (*httptest.Server, http.Client) {
+// setupMockClient builds a client whose every request goes to handler, no
+// matter where it was meant for. Everything is torn down when the test is done.
+func setupMockClient(t *testing.T, handler http.Handler) *Client {
 	/*
 		Originally I wanted to just have the API make requests to the HTTPS endpoints
 		that Favor sets up. Unfortunately, I couldn't get httptest.NewTLSServer working
@@ -24,23 +29,50 @@ func setupMockClient(response string) (*httptest.Server, http.Client) {
 
 		This code is lovingly borrowed from http://keighl.com/post/mocking-http-responses-in-golang/
 	*/
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
 
-	// Test server that always responds with 200 code, and specific payload
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(200)
-		w.Header().Set("Content-Type", "application/json")
-		fmt.Fprintln(w, response)
-	}))
+	s, err := New(dummyToken)
+	if err != nil {
+		t.Fatalf("Constructor failed with the following error: %v", err)
+	}
+	s.Secure = false
 
 	// Make a transport that reroutes all traffic to the example server
-	transport := &http.Transport{
+	s.Client = http.Client{Transport: &http.Transport{
 		Proxy: func(req *http.Request) (*url.URL, error) {
 			return url.Parse(server.URL)
 		},
+	}}
+	return s
+}
+
+// respondWith is a handler that answers every request with the same payload.
+func respondWith(response string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, response)
 	}
-	// Make a http.Client with the transport
-	httpClient := http.Client{Transport: transport}
-	return server, httpClient
+}
+
+// recordedRequest is what a server from setupRecordingClient last received.
+type recordedRequest struct {
+	Method string
+	Path   string
+	Form   url.Values
+}
+
+// setupRecordingClient is setupMockClient answering with response, but it also
+// keeps track of the last request it was sent, for tests that care about what
+// got sent and not just what came back.
+func setupRecordingClient(t *testing.T, response string) (*Client, *recordedRequest) {
+	recorded := &recordedRequest{}
+	s := setupMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		*recorded = recordedRequest{Method: r.Method, Path: r.URL.Path, Form: r.PostForm}
+		fmt.Fprintln(w, response)
+	}))
+	return s, recorded
 }
 
 func TestBadTokenInput(t *testing.T) {
@@ -59,3 +91,68 @@ func TestURLConstruction(t *testing.T) {
 		t.Errorf("Expected URL to be: %v\nActually generated: %v\n", expectedURL, actualURL)
 	}
 }
+
+func TestRequestsWithBodyPrintNothing(t *testing.T) {
+	s := setupMockClient(t, respondWith(`{"secret": "response"}`))
+
+	// requests used to be dumped to stdout, token and all
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Creating a pipe failed: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	_, err = s.makeAPIRequestWithBody("post", s.BuildURL("favors/", map[string]string{}), url.Values{"wants": {"tacos"}})
+	os.Stdout = stdout
+	w.Close()
+	if err != nil {
+		t.Errorf("Request failed with the following error: %v", err)
+	}
+
+	printed, _ := ioutil.ReadAll(r)
+	if len(printed) > 0 {
+		t.Errorf("Nothing should be printed while making a request, but this was:\n%v", string(printed))
+	}
+}
+
+func TestRequestsWithBodyAreForms(t *testing.T) {
+	var contentType, wants string
+	s := setupMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		wants = r.FormValue("wants")
+		fmt.Fprintln(w, `{}`)
+	}))
+
+	for _, method := range []string{"post", "put"} {
+		_, err := s.makeAPIRequestWithBody(method, s.BuildURL("favors/", map[string]string{}), url.Values{"wants": {"tacos"}})
+		if err != nil {
+			t.Errorf("Request failed with the following error: %v", err)
+		}
+		if contentType != "application/x-www-form-urlencoded" {
+			t.Errorf("Expected a %v request to be sent as a form, but its Content-Type was %q", method, contentType)
+		}
+		if wants != "tacos" {
+			t.Errorf("Expected the server to be able to read the %v form, but it read wants as %q", method, wants)
+		}
+	}
+}
+
+func TestUnsuccessfulResponsesAreStatusErrors(t *testing.T) {
+	s := setupMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such favor", http.StatusNotFound)
+	}))
+
+	_, err := s.GetFavor("404")
+	se := &StatusError{}
+	if !errors.As(err, &se) {
+		t.Fatalf("Expected a StatusError, got %v", err)
+	}
+	if se.StatusCode != http.StatusNotFound || se.Body != "no such favor" {
+		t.Errorf("Expected a 404 saying there's no such favor, got %v %q", se.StatusCode, se.Body)
+	}
+
+	_, err = s.makeAPIRequestWithBody("post", s.BuildURL("favors/", map[string]string{}), url.Values{})
+	if !errors.As(err, &se) {
+		t.Errorf("Expected requests with bodies to return StatusErrors too, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// tokenEnvironmentVariable is where we look for a token before bothering with
+// the config file.
+const tokenEnvironmentVariable = "FAVOR_TOKEN"
+
+// config is what lives in the config file. Either the token goes in the file
+// directly, or TokenFile points at a file containing it, which is held to the
+// usual 0600 standard.
+type config struct {
+	Token     string `json:"token,omitempty"`
+	TokenFile string `json:"token_file,omitempty"`
+}
+
+func defaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "favor.json"
+	}
+	return filepath.Join(home, ".config", "favor", "config.json")
+}
+
+func loadConfig(path string) (config, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return config{}, err
+	}
+	cfg := config{}
+	if err := json.Unmarshal(contents, &cfg); err != nil {
+		return config{}, fmt.Errorf("the config file %v is not valid JSON: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// clientFromEnvironment builds a client using the token from FAVOR_TOKEN, or
+// from the config file if that's not set.
+func clientFromEnvironment(configPath string) (*favor.Client, error) {
+	if os.Getenv(tokenEnvironmentVariable) != "" {
+		return favor.NewWithTokenSource(favor.EnvToken{Name: tokenEnvironmentVariable})
+	}
+
+	cfg, err := loadConfig(configPath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no token found, set %v or create %v", tokenEnvironmentVariable, configPath)
+	} else if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case cfg.Token != "":
+		return favor.New(cfg.Token)
+	case cfg.TokenFile != "":
+		return favor.NewWithTokenSource(favor.FileToken{Path: cfg.TokenFile})
+	default:
+		return nil, fmt.Errorf("the config file %v has neither a token nor a token_file", configPath)
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/store"
+	"github.com/verygoodsoftwarenotvirus/favor/tui"
+)
+
+func favorRow(f favor.Favor) []string {
+	created := "-"
+	if f.CreatedAt > 0 {
+		created = time.Unix(int64(f.CreatedAt), 0).Format("2006-01-02 15:04")
+	}
+	return []string{
+		f.ID,
+		orDash(f.Title),
+		orDash(f.Stage),
+		orDash(f.LastStatus),
+		orDash(strings.TrimSpace(f.Runner.Forename + " " + f.Runner.Surname)),
+		orDash(f.Receipt.Paid),
+		created,
+	}
+}
+
+// interruptible returns a context that's cancelled by ctrl+c, so the commands
+// that run until a favor is finished can stop cleanly.
+func interruptible() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		select {
+		case <-interrupts:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(interrupts)
+	}()
+	return ctx, cancel
+}
+
+var favorHeader = []string{"ID", "TITLE", "STAGE", "STATUS", "RUNNER", "PAID", "CREATED"}
+
+func (c cli) favorsList(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected no arguments")
+	}
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	favors, err := client.GetFavors()
+	if err != nil {
+		return err
+	}
+	return c.print(favors, favorHeader, func() [][]string {
+		rows := [][]string{}
+		for _, f := range favors {
+			rows = append(rows, favorRow(f))
+		}
+		return rows
+	})
+}
+
+func (c cli) favorsShow(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a favor ID")
+	}
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	f, err := client.GetFavor(args[0])
+	if err != nil {
+		return err
+	}
+	return c.print(f, favorHeader, func() [][]string {
+		return [][]string{favorRow(f)}
+	})
+}
+
+func (c cli) favorsPlace(args []string) error {
+	flags := flag.NewFlagSet("favors place", flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	rf := favor.RequestFavor{}
+	flags.StringVar(&rf.Title, "title", "", "what to call the favor, usually the merchant's name")
+	flags.StringVar(&rf.Wants, "wants", "", "what you want")
+	flags.Float64Var(&rf.Lat, "lat", 0, "delivery latitude")
+	flags.Float64Var(&rf.Lng, "lng", 0, "delivery longitude")
+	flags.StringVar(&rf.Street, "street", "", "delivery street address")
+	flags.StringVar(&rf.Zipcode, "zip", "", "delivery zipcode")
+	flags.StringVar(&rf.Apt, "apt", "", "delivery apartment number")
+	flags.StringVar(&rf.Notes, "notes", "", "notes for the runner")
+	flags.IntVar(&rf.MerchantID, "merchant", 0, "merchant ID, if ordering from a known merchant")
+	flags.IntVar(&rf.MarketID, "market", 0, "market ID, figured out from the coordinates if left out")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := rf.Validate(nil); err != nil {
+		return err
+	}
+
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	f, err := client.PlaceFavor(rf)
+	if err != nil {
+		return err
+	}
+	return c.print(f, favorHeader, func() [][]string {
+		return [][]string{favorRow(f)}
+	})
+}
+
+func (c cli) favorsWatch(args []string) error {
+	flags := flag.NewFlagSet("favors watch", flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	interval := flags.Duration("interval", favor.DefaultWatchInterval, "how often to check on the favor")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected a favor ID")
+	}
+
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := interruptible()
+	defer cancel()
+
+	first := true
+	err = client.WatchFavor(ctx, flags.Arg(0), *interval, func(f favor.Favor, changes favor.FavorChanges) error {
+		if c.format == "json" {
+			return c.printJSON(f)
+		}
+		if first {
+			first = false
+			return c.printTable(append([]string{"TIME"}, favorHeader...), [][]string{append([]string{time.Now().Format("15:04:05")}, favorRow(f)...)})
+		}
+		return c.printTable(nil, [][]string{append([]string{time.Now().Format("15:04:05")}, favorRow(f)...)})
+	})
+	if err == context.Canceled {
+		return nil
+	}
+	return err
+}
+
+func (c cli) favorsOrder(args []string) error {
+	flags := flag.NewFlagSet("favors order", flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	address := favor.Address{}
+	flags.StringVar(&address.Street, "street", "", "delivery street address, asked for if left out")
+	flags.StringVar(&address.Zipcode, "zip", "", "delivery zipcode, asked for if left out")
+	flags.StringVar(&address.Apartment, "apt", "", "delivery apartment number")
+	interval := flags.Duration("interval", favor.DefaultWatchInterval, "how often to check on the favor once it's placed")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return fmt.Errorf("expected a latitude and longitude")
+	}
+	near, err := favor.ParseLatLng(flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := interruptible()
+	defer cancel()
+
+	ui := &tui.UI{
+		Client:        client,
+		In:            c.stdin,
+		Out:           c.stdout,
+		Address:       address,
+		WatchInterval: *interval,
+	}
+	return ui.Run(ctx, near)
+}
+
+// defaultStorePath is where favors sync keeps its history, next to the config.
+func defaultStorePath() string {
+	return filepath.Join(filepath.Dir(defaultConfigPath()), "favors.db")
+}
+
+func (c cli) favorsSync(args []string) error {
+	flags := flag.NewFlagSet("favors sync", flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	path := flags.String("db", defaultStorePath(), "path to the history file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("expected no arguments")
+	}
+
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(*path), 0700); err != nil {
+		return err
+	}
+	s, err := store.OpenBoltStore(*path)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	result, err := store.Sync(client, s)
+	if err != nil {
+		return err
+	}
+	return c.print(result, []string{"ADDED", "UPDATED", "UNCHANGED"}, func() [][]string {
+		return [][]string{{fmt.Sprint(result.Added), fmt.Sprint(result.Updated), fmt.Sprint(result.Unchanged)}}
+	})
+}
@@ -0,0 +1,106 @@
+// Command favor is a command line interface for the Favor API, so that nobody
+// has to write another throwaway main.go to check on their tacos.
+//
+// Usage:
+//
+//	favor [-o table|json] [-config path] <command> <subcommand> [arguments]
+//
+// The token is read from the FAVOR_TOKEN environment variable if it's set, and
+// from the config file (~/.config/favor/config.json by default) otherwise.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+const usage = `usage: favor [-o table|json] [-config path] <command> <subcommand> [arguments]
+
+commands:
+  favors list                     list your favors
+  favors show <id>                show a single favor
+  favors place [flags]            place a favor, see favor favors place -h
+  favors watch <id>               follow a favor until it's finished
+  favors order <lat> <lng>        order interactively from merchants near a point
+  favors sync [-db path]          copy your favors into a history file that keeps them
+  merchants near <lat> <lng>      list merchants near a point
+  merchants show <id>             show a single merchant
+  merchants hours <id>            show when a merchant is open
+`
+
+// cli holds everything a command needs to do its job.
+type cli struct {
+	stdin     io.Reader
+	stdout    io.Writer
+	stderr    io.Writer
+	format    string
+	newClient func() (*favor.Client, error)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("favor", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { fmt.Fprint(stderr, usage) }
+	format := flags.String("o", "table", "output format, either table or json")
+	configPath := flags.String("config", defaultConfigPath(), "path to the config file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	c := cli{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		format: *format,
+		newClient: func() (*favor.Client, error) {
+			return clientFromEnvironment(*configPath)
+		},
+	}
+	return c.run(flags.Args())
+}
+
+func (c cli) run(args []string) int {
+	if c.format != "table" && c.format != "json" {
+		fmt.Fprintf(c.stderr, "unknown output format %q, expected table or json\n", c.format)
+		return 2
+	}
+	if len(args) < 2 {
+		fmt.Fprint(c.stderr, usage)
+		return 2
+	}
+
+	commands := map[string]map[string]func([]string) error{
+		"favors": {
+			"list":  c.favorsList,
+			"show":  c.favorsShow,
+			"place": c.favorsPlace,
+			"watch": c.favorsWatch,
+			"order": c.favorsOrder,
+			"sync":  c.favorsSync,
+		},
+		"merchants": {
+			"near":  c.merchantsNear,
+			"show":  c.merchantsShow,
+			"hours": c.merchantsHours,
+		},
+	}
+
+	command, ok := commands[args[0]][args[1]]
+	if !ok {
+		fmt.Fprintf(c.stderr, "unknown command %q\n\n%v", args[0]+" "+args[1], usage)
+		return 2
+	}
+	if err := command(args[2:]); err != nil {
+		fmt.Fprintf(c.stderr, "favor %v %v: %v\n", args[0], args[1], err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/favortest"
+)
+
+var dummyToken = "thisisarandomstringfortestinglol"
+
+func buildTestCLI(t *testing.T, format string, handler http.HandlerFunc) (cli, *bytes.Buffer, *bytes.Buffer) {
+	client := favortest.NewClient(t, handler)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	c := cli{
+		stdout: stdout,
+		stderr: stderr,
+		format: format,
+		newClient: func() (*favor.Client, error) {
+			return client, nil
+		},
+	}
+	return c, stdout, stderr
+}
+
+func TestMerchantsNear(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"merchants": [
+			{"id": "1", "name": "Far Away Tacos", "lat": "30.4", "lng": "-97.7"},
+			{"id": "2", "name": "Close Tacos", "lat": "30.2672", "lng": "-97.7431"}
+		]}`)
+	}
+
+	c, stdout, _ := buildTestCLI(t, "table", handler)
+	assert.Equal(t, 0, c.run([]string{"merchants", "near", "30.2672", "-97.7431"}))
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.True(t, strings.HasPrefix(lines[0], "ID"))
+		assert.Contains(t, lines[1], "Close Tacos")
+		assert.Contains(t, lines[2], "Far Away Tacos")
+	}
+
+	c, stdout, _ = buildTestCLI(t, "json", handler)
+	assert.Equal(t, 0, c.run([]string{"merchants", "near", "30.2672", "-97.7431"}))
+	merchants := []favor.Merchant{}
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &merchants))
+	assert.Len(t, merchants, 2)
+
+	c, _, stderr := buildTestCLI(t, "table", handler)
+	assert.Equal(t, 1, c.run([]string{"merchants", "near", "north", "south"}))
+	assert.Contains(t, stderr.String(), "latitude")
+}
+
+func TestFavorsShow(t *testing.T) {
+	c, stdout, _ := buildTestCLI(t, "table", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"favor": {"id": "9876", "title": "Torchy's Tacos", "stage": "assigned", "runner": {"forename": "Speedy"}}}`)
+	})
+
+	assert.Equal(t, 0, c.run([]string{"favors", "show", "9876"}))
+	assert.Contains(t, stdout.String(), "Torchy's Tacos")
+	assert.Contains(t, stdout.String(), "Speedy")
+}
+
+func TestUnknownCommand(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(t, 2, run([]string{"favors", "eat"}, nil, stdout, stderr))
+	assert.Contains(t, stderr.String(), "unknown command")
+	assert.Equal(t, 2, run([]string{"-o", "xml", "favors", "list"}, nil, stdout, stderr))
+}
+
+func TestClientFromEnvironment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "favor")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	os.Unsetenv(tokenEnvironmentVariable)
+	path := filepath.Join(dir, "config.json")
+
+	_, err = clientFromEnvironment(path)
+	assert.NotNil(t, err, "A missing config file should be an error")
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte(fmt.Sprintf(`{"token": %q}`, dummyToken)), 0600))
+	client, err := clientFromEnvironment(path)
+	assert.Nil(t, err)
+	assert.Equal(t, dummyToken, client.Token)
+
+	os.Setenv(tokenEnvironmentVariable, "thisisanotherrandomstringfortest")
+	defer os.Unsetenv(tokenEnvironmentVariable)
+	client, err = clientFromEnvironment(path)
+	assert.Nil(t, err)
+	assert.NotNil(t, client.TokenSource, "The environment should win over the config file")
+}
+
+func TestFavorsSync(t *testing.T) {
+	dir, err := ioutil.TempDir("", "favor")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "history", "favors.db")
+
+	c, stdout, _ := buildTestCLI(t, "json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"favors": [{"id": "9876", "title": "Torchy's Tacos", "stage": "delivered"}]}`)
+	})
+
+	assert.Equal(t, 0, c.run([]string{"favors", "sync", "-db", path}))
+	assert.Contains(t, stdout.String(), `"Added": 1`)
+	stdout.Reset()
+	assert.Equal(t, 0, c.run([]string{"favors", "sync", "-db", path}))
+	assert.Contains(t, stdout.String(), `"Unchanged": 1`)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+var merchantHeader = []string{"ID", "NAME", "ADDRESS", "CITY", "PHONE", "DISTANCE", "OPEN"}
+
+func merchantRow(m favor.Merchant, now time.Time) []string {
+	open := "no"
+	if len(m.Hours) == 0 {
+		open = "?"
+	} else if m.IsOpenAt(now) {
+		open = "yes"
+	}
+	distance := "-"
+	if m.Distance > 0 {
+		distance = strconv.FormatFloat(m.Distance, 'f', 2, 64)
+	}
+	return []string{m.ID, orDash(m.Name), orDash(m.Address), orDash(m.City), orDash(m.Phone), distance, open}
+}
+
+func (c cli) merchantsNear(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected a latitude and longitude")
+	}
+	p, err := favor.ParseLatLng(args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	merchants, err := client.GetMerchants(p.Lat, p.Lng)
+	if err != nil {
+		return err
+	}
+	sorted := favor.Merchants(merchants).SortByDistance(p)
+
+	now := time.Now()
+	return c.print(sorted, merchantHeader, func() [][]string {
+		rows := [][]string{}
+		for _, m := range sorted {
+			rows = append(rows, merchantRow(m, now))
+		}
+		return rows
+	})
+}
+
+func (c cli) merchantsShow(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a merchant ID")
+	}
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	m, err := client.GetMerchant(args[0])
+	if err != nil {
+		return err
+	}
+	return c.print(m, merchantHeader, func() [][]string {
+		return [][]string{merchantRow(m, time.Now())}
+	})
+}
+
+func (c cli) merchantsHours(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a merchant ID")
+	}
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	m, err := client.GetMerchant(args[0])
+	if err != nil {
+		return err
+	}
+	if len(m.Hours) == 0 && c.format != "json" {
+		fmt.Fprintf(c.stdout, "%v has no hours listed\n", m.Name)
+		return nil
+	}
+
+	hours := []string{}
+	for _, h := range m.Hours {
+		hours = append(hours, h.String())
+	}
+	return c.print(struct {
+		Merchant string   `json:"merchant"`
+		OpenNow  bool     `json:"open_now"`
+		Hours    []string `json:"hours"`
+	}{m.Name, m.IsOpenAt(time.Now()), hours}, []string{"HOURS"}, func() [][]string {
+		rows := [][]string{}
+		for _, h := range hours {
+			rows = append(rows, []string{h})
+		}
+		return rows
+	})
+}
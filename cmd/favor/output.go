@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// printJSON writes v out as indented JSON.
+func (c cli) printJSON(v interface{}) error {
+	encoder := json.NewEncoder(c.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// printTable writes out rows of columns lined up under a header, if there is one.
+func (c cli) printTable(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	if len(header) > 0 {
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// print writes v as JSON, or as a table built by rows, depending on the output format.
+func (c cli) print(v interface{}, header []string, rows func() [][]string) error {
+	if c.format == "json" {
+		return c.printJSON(v)
+	}
+	return c.printTable(header, rows())
+}
+
+// orDash keeps empty cells from making tables hard to read.
+func orDash(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,97 @@
+// Command favord runs the server package's REST gateway in front of the Favor
+// API.
+//
+// Usage:
+//
+//	favord [-addr :8080] [-grpc-addr :9090] -keys keys.json
+//
+// The key file maps each caller's API key to the Favor token their requests
+// are made with; see server.LoadKeys for what it looks like. It should be
+// readable by nobody but whoever runs favord. If -grpc-addr is set, the gRPC
+// service from the grpcserver package is served there too, with the same keys.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor/grpcserver"
+	"github.com/verygoodsoftwarenotvirus/favor/server"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+func run(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("favord", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	grpcAddr := flags.String("grpc-addr", "", "address to serve gRPC on, if any")
+	keyPath := flags.String("keys", "", "path to the API key file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *keyPath == "" {
+		fmt.Fprintln(stderr, "favord: -keys is required")
+		return 2
+	}
+
+	keys, err := server.LoadKeys(*keyPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "favord: %v\n", err)
+		return 1
+	}
+	logger := log.New(stderr, "favord ", log.LstdFlags)
+	s := &server.Server{Keys: keys, Logger: logger}
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *grpcAddr != "" {
+		listener, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			fmt.Fprintf(stderr, "favord: %v\n", err)
+			return 1
+		}
+		g := (&grpcserver.Server{Keys: keys}).GRPC()
+		go func() {
+			<-ctx.Done()
+			g.GracefulStop()
+		}()
+		go func() {
+			if err := g.Serve(listener); err != nil {
+				logger.Printf("gRPC stopped: %v", err)
+			}
+		}()
+		logger.Printf("serving gRPC on %v", *grpcAddr)
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdown)
+	}()
+
+	logger.Printf("listening on %v with %d callers", *addr, len(keys))
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		fmt.Fprintf(stderr, "favord: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunRequiresKeys(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	assert.Equal(t, 2, run([]string{}, stderr))
+	assert.Contains(t, stderr.String(), "-keys is required")
+
+	dir, err := ioutil.TempDir("", "favord")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys.json")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`{"callers": [{"name": "billing"}]}`), 0600))
+
+	stderr.Reset()
+	assert.Equal(t, 1, run([]string{"-keys", path}, stderr))
+	assert.Contains(t, stderr.String(), "needs both a name and a key")
+}
@@ -0,0 +1,184 @@
+package favor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultMaxFailures is how many polls in a row can fail before a FavorTracker
+// gives up, if it isn't told otherwise.
+const DefaultMaxFailures = 5
+
+// EventType describes what happened to a favor.
+type EventType string
+
+// These are the events a FavorTracker can report. A single poll can turn up
+// several of them for the same favor, like a runner being assigned at the
+// same time as the stage changing.
+const (
+	EventCreated        EventType = "favor.created"
+	EventStageChanged   EventType = "favor.stage_changed"
+	EventStatusChanged  EventType = "favor.status_changed"
+	EventRunnerChanged  EventType = "favor.runner_changed"
+	EventReceiptChanged EventType = "favor.receipt_changed"
+	EventFinished       EventType = "favor.finished"
+)
+
+// FavorEvent is something that happened to a favor. Previous is the favor as
+// it was before, and is nil for EventCreated.
+type FavorEvent struct {
+	Type     EventType `json:"type"`
+	Favor    Favor     `json:"favor"`
+	Previous *Favor    `json:"previous,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// eventsBetween returns the events it took to get from previous to current.
+func eventsBetween(previous, current Favor, at time.Time) []FavorEvent {
+	changes := current.ChangesFrom(previous)
+	types := []EventType{}
+	if changes.Stage {
+		types = append(types, EventStageChanged)
+	}
+	if changes.LastStatus {
+		types = append(types, EventStatusChanged)
+	}
+	if changes.Runner {
+		types = append(types, EventRunnerChanged)
+	}
+	if changes.Receipt {
+		types = append(types, EventReceiptChanged)
+	}
+	if current.IsFinished() && !previous.IsFinished() {
+		types = append(types, EventFinished)
+	}
+
+	events := []FavorEvent{}
+	for _, t := range types {
+		events = append(events, FavorEvent{Type: t, Favor: current, Previous: &previous, At: at})
+	}
+	return events
+}
+
+// FavorTracker keeps an eye on every favor a Client can see, and reports what
+// changes about them. The first poll only takes note of the favors that are
+// already there, so that starting a tracker doesn't replay history. Favors that
+// haven't finished yet are followed individually if they stop showing up in
+// GetFavors, which only returns recent ones.
+//
+// Every poll moves the tracker along, so each event only comes out of it once.
+// If a few things need the same events, give them a Run each with their own
+// tracker, or hand every event to all of them from a single Run.
+//
+// OnError, if it's set, hears about every poll that fails while running, and
+// Run gives up after MaxFailures of them in a row.
+type FavorTracker struct {
+	Client      *Client
+	Interval    time.Duration
+	OnError     func(error)
+	MaxFailures int
+
+	lock  sync.Mutex
+	known map[string]Favor
+}
+
+// Poll checks on the favors once, and returns whatever happened since the last
+// time it was called.
+func (t *FavorTracker) Poll() ([]FavorEvent, error) {
+	favors, err := t.Client.GetFavors()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.known == nil {
+		t.known = map[string]Favor{}
+		for _, f := range favors {
+			t.known[f.ID] = f
+		}
+		return []FavorEvent{}, nil
+	}
+
+	seen := map[string]bool{}
+	for _, f := range favors {
+		seen[f.ID] = true
+	}
+	for id, f := range t.known {
+		if seen[id] || f.IsFinished() {
+			continue
+		}
+		if current, err := t.Client.GetFavor(id); err == nil && current.ID != "" {
+			favors = append(favors, current)
+		}
+	}
+
+	events := []FavorEvent{}
+	for _, f := range favors {
+		previous, ok := t.known[f.ID]
+		t.known[f.ID] = f
+		if !ok {
+			events = append(events, FavorEvent{Type: EventCreated, Favor: f, At: now})
+			if f.IsFinished() {
+				events = append(events, FavorEvent{Type: EventFinished, Favor: f, At: now})
+			}
+			continue
+		}
+		events = append(events, eventsBetween(previous, f, now)...)
+	}
+	return events, nil
+}
+
+// Run polls every Interval, and calls fn with every event, until fn returns an
+// error or the context is done. Failed polls are retried at the next interval,
+// unless the very first one fails, or too many have failed in a row.
+func (t *FavorTracker) Run(ctx context.Context, fn func(FavorEvent) error) error {
+	interval := t.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	maxFailures := t.MaxFailures
+	if maxFailures <= 0 {
+		maxFailures = DefaultMaxFailures
+	}
+	t.lock.Lock()
+	started := t.known != nil
+	t.lock.Unlock()
+	if !started {
+		if _, err := t.Poll(); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		events, err := t.Poll()
+		if err != nil {
+			failures++
+			if t.OnError != nil {
+				t.OnError(err)
+			}
+			if failures >= maxFailures {
+				return fmt.Errorf("Polling for favors failed %d times in a row, most recently with this error:\n %v", failures, err)
+			}
+			continue
+		}
+		failures = 0
+		for _, e := range events {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+	}
+}
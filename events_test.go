@@ -0,0 +1,95 @@
+package favor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFavorTracker(t *testing.T) {
+	var lock sync.Mutex
+	list := `[{"id": "1", "stage": "pending"}, {"id": "2", "stage": "delivered"}]`
+	single := `{"id": "1", "stage": "pending"}`
+	setResponses := func(l, s string) {
+		lock.Lock()
+		defer lock.Unlock()
+		list, single = l, s
+	}
+
+	s := setupMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if r.URL.Path == "/api/v5/favors/" {
+			fmt.Fprintf(w, `{"favors": %v}`, list)
+		} else {
+			fmt.Fprintf(w, `{"favor": %v}`, single)
+		}
+	}))
+	tracker := &FavorTracker{Client: s, Interval: time.Millisecond}
+
+	events, err := tracker.Poll()
+	assert.Nil(t, err)
+	assert.Empty(t, events, "The first poll shouldn't report favors that were already there")
+
+	setResponses(`[{"id": "1", "stage": "assigned", "runner": {"id": "77"}}, {"id": "3", "stage": "pending"}]`, "")
+	events, err = tracker.Poll()
+	assert.Nil(t, err)
+	types := []EventType{}
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []EventType{EventStageChanged, EventRunnerChanged, EventCreated}, types)
+	assert.Equal(t, "pending", events[0].Previous.Stage)
+
+	setResponses(`[{"id": "3", "stage": "pending"}]`, `{"id": "1", "stage": "delivered", "runner": {"id": "77"}}`)
+	events, err = tracker.Poll()
+	assert.Nil(t, err)
+	if assert.Len(t, events, 2, "Unfinished favors should be followed after they drop off the list") {
+		assert.Equal(t, EventStageChanged, events[0].Type)
+		assert.Equal(t, EventFinished, events[1].Type)
+		assert.Equal(t, "1", events[1].Favor.ID)
+	}
+
+	setResponses(`[{"id": "3", "stage": "cancelled"}]`, "")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	seen := []EventType{}
+	err = tracker.Run(ctx, func(e FavorEvent) error {
+		seen = append(seen, e.Type)
+		if e.Type == EventFinished {
+			return fmt.Errorf("done")
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "done")
+	assert.Equal(t, []EventType{EventStageChanged, EventFinished}, seen, "Run should pick up where Poll left off")
+}
+
+func TestFavorTrackerGivesUp(t *testing.T) {
+	var polls int32
+
+	s := setupMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&polls, 1) == 1 {
+			fmt.Fprintln(w, `{"favors": []}`)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	failures := []error{}
+	tracker := &FavorTracker{Client: s, Interval: time.Millisecond, MaxFailures: 3, OnError: func(err error) {
+		failures = append(failures, err)
+	}}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := tracker.Run(ctx, func(e FavorEvent) error { return nil })
+	assert.NotNil(t, err)
+	assert.NotEqual(t, context.DeadlineExceeded, err, "Run should give up on its own")
+	assert.Len(t, failures, 3, "Every failed poll should be reported")
+}
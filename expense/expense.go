@@ -0,0 +1,118 @@
+// Package expense turns favors into things accountants can use: CSV, OFX and
+// QIF transaction files for importing into whatever they keep the books in,
+// and a printable HTML receipt for each order. Every entry is tagged with a
+// cost center, worked out from who ordered it or where it went.
+package expense
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// CostCenters decides which cost center a favor gets charged to. Addresses are
+// keyed by address ID or zipcode, and Customers by customer ID or email.
+// Addresses are checked first, since where something was delivered is
+// usually a better sign of which team it was for than who ordered it. Favors
+// that match nothing get Default.
+type CostCenters struct {
+	Addresses map[string]string `json:"addresses,omitempty"`
+	Customers map[string]string `json:"customers,omitempty"`
+	Default   string            `json:"default,omitempty"`
+}
+
+// LoadCostCenters reads cost centers from a JSON file, like
+//
+//	{"addresses": {"78701": "austin-office"}, "customers": {"greg@example.com": "sales"}, "default": "general"}
+func LoadCostCenters(path string) (CostCenters, error) {
+	cc := CostCenters{}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cc, err
+	}
+	if err := json.Unmarshal(contents, &cc); err != nil {
+		return cc, fmt.Errorf("the cost center file %v is not valid JSON: %v", path, err)
+	}
+	return cc, nil
+}
+
+// Tag returns the cost center for a favor.
+func (cc CostCenters) Tag(f favor.Favor) string {
+	lookups := []struct {
+		table map[string]string
+		key   string
+	}{
+		{cc.Addresses, f.DeliveryAddress.ID},
+		{cc.Addresses, f.DeliveryAddress.Zipcode},
+		{cc.Customers, f.Customer.ID},
+		{cc.Customers, strings.ToLower(f.Customer.Email)},
+	}
+	for _, l := range lookups {
+		if l.key == "" {
+			continue
+		}
+		if tag, ok := l.table[l.key]; ok {
+			return tag
+		}
+	}
+	return cc.Default
+}
+
+// Entry is one favor as an expense. Payee is who the money went to, which is
+// the merchant.
+type Entry struct {
+	Favor      favor.Favor
+	Amounts    favor.ReceiptAmounts
+	Date       time.Time
+	Payee      string
+	CostCenter string
+}
+
+// Memo describes the entry in a line, for the memo fields of the various
+// formats.
+func (e Entry) Memo() string {
+	memo := fmt.Sprintf("Favor #%v", e.Favor.ID)
+	if customer := strings.TrimSpace(e.Favor.Customer.Forename + " " + e.Favor.Customer.Surname); customer != "" {
+		memo = fmt.Sprintf("%v for %v", memo, customer)
+	}
+	if len(e.Favor.Items) > 0 {
+		memo = fmt.Sprintf("%v: %v", memo, strings.Join(e.Favor.Items, ", "))
+	}
+	return memo
+}
+
+// Entries turns favors into expense entries, tagged with cost centers. Favors
+// that haven't been paid for yet, so have nothing on their receipt, are left
+// out. A receipt that can't be read, or a paid favor with no idea when it was
+// made, is an error, rather than a hole in somebody's books.
+func Entries(favors []favor.Favor, cc CostCenters) ([]Entry, error) {
+	entries := []Entry{}
+	for _, f := range favors {
+		ra, err := f.Receipt.Amounts()
+		if err != nil {
+			return nil, fmt.Errorf("Error reading the receipt for favor %v!:\n%v", f.ID, err)
+		}
+		if ra.Total() == 0 {
+			continue
+		}
+		if f.CreatedAt == 0 {
+			return nil, fmt.Errorf("Favor %v has been paid for, but has no creation date to file it under.", f.ID)
+		}
+		payee := f.Merchant.Name
+		if payee == "" {
+			payee = f.Title
+		}
+		entries = append(entries, Entry{
+			Favor:      f,
+			Amounts:    ra,
+			Date:       time.Unix(int64(f.CreatedAt), 0).UTC(),
+			Payee:      payee,
+			CostCenter: cc.Tag(f),
+		})
+	}
+	return entries, nil
+}
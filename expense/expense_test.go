@@ -0,0 +1,105 @@
+package expense
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/favortest"
+)
+
+var march = time.Date(2016, time.March, 9, 12, 30, 0, 0, time.UTC)
+
+// torchys is the favor every test here starts from. They fill in an ID, and a
+// price if it's been paid for.
+var torchys = favortest.Spec{
+	Title:    "Torchy's Tacos",
+	Items:    []string{"Trailer Park", "chips & queso"},
+	Created:  march,
+	Customer: favor.User{ID: "10", Forename: "Greg", Surname: "Salt", Email: "Greg@example.com"},
+	Address:  favor.Address{ID: "20", Street: "123 Fake St", Zipcode: "78701"},
+	Tip:      "2.00",
+}
+
+func TestCostCentersTag(t *testing.T) {
+	spec := torchys
+	spec.ID, spec.Price = "1", "10.00"
+	f := spec.Favor()
+	assert.Equal(t, "", CostCenters{}.Tag(f))
+	assert.Equal(t, "general", CostCenters{Default: "general"}.Tag(f))
+
+	cc := CostCenters{
+		Customers: map[string]string{"greg@example.com": "sales"},
+		Default:   "general",
+	}
+	assert.Equal(t, "sales", cc.Tag(f), "emails should match regardless of case")
+
+	cc.Customers["10"] = "greg"
+	assert.Equal(t, "greg", cc.Tag(f))
+
+	cc.Addresses = map[string]string{"78701": "austin-office"}
+	assert.Equal(t, "austin-office", cc.Tag(f))
+
+	cc.Addresses["20"] = "front-desk"
+	assert.Equal(t, "front-desk", cc.Tag(f))
+}
+
+func TestLoadCostCenters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "expense")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cost_centers.json")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`{"addresses": {"78701": "austin-office"}, "default": "general"}`), 0600))
+	cc, err := LoadCostCenters(path)
+	assert.Nil(t, err)
+	assert.Equal(t, CostCenters{Addresses: map[string]string{"78701": "austin-office"}, Default: "general"}, cc)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`nope`), 0600))
+	_, err = LoadCostCenters(path)
+	assert.NotNil(t, err)
+
+	_, err = LoadCostCenters(filepath.Join(dir, "missing.json"))
+	assert.NotNil(t, err)
+}
+
+func TestEntries(t *testing.T) {
+	paid, unpaid, other := torchys, torchys, torchys
+	paid.ID, paid.Price = "1", "10.00"
+	unpaid.ID, unpaid.Tip = "2", ""
+	other.ID, other.Price = "3", "20.00"
+	merchant := other.Favor()
+	merchant.Merchant = favor.Merchant{Name: "Torchy's Tacos (South Congress)"}
+
+	entries, err := Entries([]favor.Favor{paid.Favor(), unpaid.Favor(), merchant}, CostCenters{Default: "general"})
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+
+	assert.Equal(t, "1", entries[0].Favor.ID)
+	assert.Equal(t, march, entries[0].Date)
+	assert.Equal(t, "Torchy's Tacos", entries[0].Payee)
+	assert.Equal(t, "general", entries[0].CostCenter)
+	assert.Equal(t, int64(1750), entries[0].Amounts.Total())
+	assert.Equal(t, "Favor #1 for Greg Salt: Trailer Park, chips & queso", entries[0].Memo())
+	assert.Equal(t, "Torchy's Tacos (South Congress)", entries[1].Payee)
+
+	broken := torchys
+	broken.ID, broken.Price = "4", "ten dollars"
+	_, err = Entries([]favor.Favor{broken.Favor()}, CostCenters{})
+	assert.NotNil(t, err)
+
+	undated := paid
+	undated.Created = time.Time{}
+	_, err = Entries([]favor.Favor{undated.Favor()}, CostCenters{})
+	assert.NotNil(t, err, "a paid favor without a date shouldn't be filed under 1970")
+
+	unpaidUndated := unpaid
+	unpaidUndated.Created = time.Time{}
+	entries, err = Entries([]favor.Favor{unpaidUndated.Favor()}, CostCenters{})
+	assert.Nil(t, err, "favors that aren't expenses yet don't need a date")
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,203 @@
+package expense
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// csvHeader is the first row WriteCSV writes.
+var csvHeader = []string{
+	"date", "favor_id", "payee", "customer", "customer_email", "delivery_address", "cost_center",
+	"price", "tip", "delivery_charge", "card_fee", "total", "memo",
+}
+
+// WriteCSV writes entries as CSV, one row per favor, with amounts in dollars.
+func WriteCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{csvHeader}
+	for _, e := range entries {
+		customer := e.Favor.Customer
+		rows = append(rows, []string{
+			e.Date.Format("2006-01-02"),
+			e.Favor.ID,
+			e.Payee,
+			strings.TrimSpace(customer.Forename + " " + customer.Surname),
+			customer.Email,
+			strings.TrimSpace(e.Favor.DeliveryAddress.Street + " " + e.Favor.DeliveryAddress.Zipcode),
+			e.CostCenter,
+			favor.FormatCents(e.Amounts.Price),
+			favor.FormatCents(e.Amounts.Tip),
+			favor.FormatCents(e.Amounts.DeliveryCharge),
+			favor.FormatCents(e.Amounts.CcFeeAmount),
+			favor.FormatCents(e.Amounts.Total()),
+			e.Memo(),
+		})
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// WriteQIF writes entries as a QIF credit card register. The cost center goes
+// in the category, which is where most accounting software will let you
+// split things up by.
+func WriteQIF(w io.Writer, entries []Entry) error {
+	if _, err := fmt.Fprint(w, "!Type:CCard\n"); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		lines := []string{
+			"D" + e.Date.Format("01/02/2006"),
+			"T" + favor.FormatCents(-e.Amounts.Total()),
+			"N" + e.Favor.ID,
+			"P" + qifLine(e.Payee),
+			"M" + qifLine(e.Memo()),
+		}
+		if e.CostCenter != "" {
+			lines = append(lines, "L"+qifLine(e.CostCenter))
+		}
+		lines = append(lines, "^")
+		if _, err := fmt.Fprint(w, strings.Join(lines, "\n")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// qifLine keeps a value on one line, since QIF is line based.
+func qifLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// OFXOptions describe the account the transactions in an OFX file belong to.
+// AccountID is required by the format, but can be anything your accounting
+// software will recognize; Currency defaults to USD.
+type OFXOptions struct {
+	AccountID string
+	Currency  string
+	// Now is when the statement was made. Defaults to time.Now, and is mostly
+	// here for tests.
+	Now func() time.Time
+}
+
+type ofxTransaction struct {
+	Type   string `xml:"TRNTYPE"`
+	Posted string `xml:"DTPOSTED"`
+	Amount string `xml:"TRNAMT"`
+	ID     string `xml:"FITID"`
+	Name   string `xml:"NAME"`
+	Memo   string `xml:"MEMO"`
+}
+
+type ofxDocument struct {
+	XMLName xml.Name `xml:"OFX"`
+	SignOn  struct {
+		Status   ofxStatus `xml:"SONRS>STATUS"`
+		Date     string    `xml:"SONRS>DTSERVER"`
+		Language string    `xml:"SONRS>LANGUAGE"`
+	} `xml:"SIGNONMSGSRSV1"`
+	Statement struct {
+		UID      string    `xml:"TRNUID"`
+		Status   ofxStatus `xml:"STATUS"`
+		Currency string    `xml:"CCSTMTRS>CURDEF"`
+		Account  string    `xml:"CCSTMTRS>CCACCTFROM>ACCTID"`
+		// The list has its own type, so that the dates and transactions all
+		// end up in the one BANKTRANLIST.
+		List    ofxTransactionList `xml:"CCSTMTRS>BANKTRANLIST"`
+		Balance struct {
+			Amount string `xml:"BALAMT"`
+			Date   string `xml:"DTASOF"`
+		} `xml:"CCSTMTRS>LEDGERBAL"`
+	} `xml:"CREDITCARDMSGSRSV1>CCSTMTTRNRS"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxTransactionList struct {
+	Start        string           `xml:"DTSTART"`
+	End          string           `xml:"DTEND"`
+	Transactions []ofxTransaction `xml:"STMTTRN"`
+}
+
+const ofxTimeFormat = "20060102150405"
+
+// WriteOFX writes entries as an OFX 2 credit card statement. Amounts are
+// negative, since they're money going out, and each favor's ID is its
+// transaction ID, so importing the same favor twice doesn't count it twice.
+func WriteOFX(w io.Writer, entries []Entry, opts OFXOptions) error {
+	if opts.AccountID == "" {
+		return fmt.Errorf("an OFX file needs an account ID")
+	}
+	currency := opts.Currency
+	if currency == "" {
+		currency = "USD"
+	}
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	generated := now().UTC()
+
+	doc := ofxDocument{}
+	doc.SignOn.Status = ofxStatus{Code: 0, Severity: "INFO"}
+	doc.SignOn.Date = generated.Format(ofxTimeFormat)
+	doc.SignOn.Language = "ENG"
+	doc.Statement.UID = "0"
+	doc.Statement.Status = ofxStatus{Code: 0, Severity: "INFO"}
+	doc.Statement.Currency = currency
+	doc.Statement.Account = opts.AccountID
+
+	var start, end time.Time
+	var balance int64
+	for _, e := range entries {
+		if start.IsZero() || e.Date.Before(start) {
+			start = e.Date
+		}
+		if e.Date.After(end) {
+			end = e.Date
+		}
+		balance -= e.Amounts.Total()
+		name := e.Payee
+		if runes := []rune(name); len(runes) > 32 {
+			// OFX won't take names any longer than this.
+			name = string(runes[:32])
+		}
+		doc.Statement.List.Transactions = append(doc.Statement.List.Transactions, ofxTransaction{
+			Type:   "DEBIT",
+			Posted: e.Date.Format(ofxTimeFormat),
+			Amount: favor.FormatCents(-e.Amounts.Total()),
+			ID:     e.Favor.ID,
+			Name:   name,
+			Memo:   e.Memo(),
+		})
+	}
+	if start.IsZero() {
+		start, end = generated, generated
+	}
+	doc.Statement.List.Start = start.Format(ofxTimeFormat)
+	doc.Statement.List.End = end.Format(ofxTimeFormat)
+	doc.Statement.Balance.Amount = favor.FormatCents(balance)
+	doc.Statement.Balance.Date = generated.Format(ofxTimeFormat)
+
+	header := xml.Header + `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,111 @@
+package expense
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/favortest"
+)
+
+func testEntries(t *testing.T) []Entry {
+	first, second := torchys, torchys
+	first.ID, first.Price = "1", "10.00"
+	second.ID, second.Price, second.Created = "2", "20.00", march.Add(48*time.Hour)
+	entries, err := Entries(favortest.Favors(first, second), CostCenters{Default: "general"})
+	assert.Nil(t, err)
+	return entries
+}
+
+func TestWriteCSV(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WriteCSV(buf, testEntries(t)))
+
+	rows, err := csv.NewReader(buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, csvHeader, rows[0])
+	expected := []string{
+		"2016-03-09", "1", "Torchy's Tacos", "Greg Salt", "Greg@example.com", "123 Fake St 78701", "general",
+		"10.00", "2.00", "5.00", "0.50", "17.50", "Favor #1 for Greg Salt: Trailer Park, chips & queso",
+	}
+	assert.Equal(t, expected, rows[1])
+	assert.Equal(t, "27.50", rows[2][11])
+}
+
+func TestWriteQIF(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WriteQIF(buf, testEntries(t)))
+
+	expected := `!Type:CCard
+D03/09/2016
+T-17.50
+N1
+PTorchy's Tacos
+MFavor #1 for Greg Salt: Trailer Park, chips & queso
+Lgeneral
+^
+D03/11/2016
+T-27.50
+N2
+PTorchy's Tacos
+MFavor #2 for Greg Salt: Trailer Park, chips & queso
+Lgeneral
+^
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteOFX(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NotNil(t, WriteOFX(buf, testEntries(t), OFXOptions{}), "an account ID should be required")
+
+	now := func() time.Time { return march.Add(72 * time.Hour) }
+	assert.Nil(t, WriteOFX(buf, testEntries(t), OFXOptions{AccountID: "corporate-card", Now: now}))
+	assert.True(t, strings.HasPrefix(buf.String(), `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<?OFX OFXHEADER="200"`))
+
+	doc := ofxDocument{}
+	assert.Nil(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "USD", doc.Statement.Currency)
+	assert.Equal(t, "corporate-card", doc.Statement.Account)
+	assert.Equal(t, "20160309123000", doc.Statement.List.Start)
+	assert.Equal(t, "20160311123000", doc.Statement.List.End)
+	assert.Equal(t, "-45.00", doc.Statement.Balance.Amount)
+	assert.Equal(t, "20160312123000", doc.Statement.Balance.Date)
+	assert.Equal(t, []ofxTransaction{
+		{Type: "DEBIT", Posted: "20160309123000", Amount: "-17.50", ID: "1", Name: "Torchy's Tacos", Memo: "Favor #1 for Greg Salt: Trailer Park, chips & queso"},
+		{Type: "DEBIT", Posted: "20160311123000", Amount: "-27.50", ID: "2", Name: "Torchy's Tacos", Memo: "Favor #2 for Greg Salt: Trailer Park, chips & queso"},
+	}, doc.Statement.List.Transactions)
+}
+
+func TestWriteReceipt(t *testing.T) {
+	e := testEntries(t)[0]
+	e.Favor.Items = append(e.Favor.Items, "<script>alert(1)</script>")
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WriteReceipt(buf, e))
+
+	page := buf.String()
+	for _, expected := range []string{
+		"<title>Receipt for favor #1</title>",
+		"<h1>Torchy&#39;s Tacos</h1>",
+		"March 9, 2016 12:30 PM UTC",
+		"Ordered by Greg Salt &lt;Greg@example.com&gt;",
+		"Delivered to 123 Fake St 78701",
+		"Cost center: general",
+		"$17.50",
+		"&lt;script&gt;",
+	} {
+		assert.Contains(t, page, expected)
+	}
+	assert.NotContains(t, page, "<script>")
+
+	e.Favor.Customer = favor.User{ID: "10"}
+	buf.Reset()
+	assert.Nil(t, WriteReceipt(buf, e))
+	assert.NotContains(t, buf.String(), "Ordered by", "a customer without a name shouldn't be an empty line")
+}
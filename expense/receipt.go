@@ -0,0 +1,54 @@
+package expense
+
+import (
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// ReceiptTemplate is the page WriteReceipt renders. It's plain enough to print,
+// and keeps its styles inline so the file stands on its own.
+var ReceiptTemplate = template.Must(template.New("receipt").Funcs(template.FuncMap{
+	"dollars": favor.FormatCents,
+	"join":    strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Receipt for favor #{{.Favor.ID}}</title>
+<style>
+body { font-family: sans-serif; max-width: 36em; margin: 2em auto; color: #222; }
+table { width: 100%; border-collapse: collapse; }
+td { padding: 0.25em 0; }
+td.amount { text-align: right; }
+tr.total td { border-top: 1px solid #222; font-weight: bold; }
+.details { color: #555; }
+</style>
+</head>
+<body>
+<h1>{{.Payee}}</h1>
+<p class="details">
+Favor #{{.Favor.ID}}<br>
+{{.Date.Format "January 2, 2006 3:04 PM MST"}}<br>
+{{with .Favor.Customer}}{{if or .Forename .Surname}}Ordered by {{.Forename}} {{.Surname}}{{with .Email}} &lt;{{.}}&gt;{{end}}<br>{{end}}{{end}}
+{{with .Favor.DeliveryAddress}}{{if .Street}}Delivered to {{.Street}}{{with .Apartment}}, {{.}}{{end}} {{.Zipcode}}<br>{{end}}{{end}}
+{{with .CostCenter}}Cost center: {{.}}{{end}}
+</p>
+{{with .Favor.Items}}<p>{{join . ", "}}</p>{{end}}
+<table>
+<tr><td>Price</td><td class="amount">${{dollars .Amounts.Price}}</td></tr>
+<tr><td>Delivery</td><td class="amount">${{dollars .Amounts.DeliveryCharge}}</td></tr>
+<tr><td>Card fee</td><td class="amount">${{dollars .Amounts.CcFeeAmount}}</td></tr>
+<tr><td>Tip</td><td class="amount">${{dollars .Amounts.Tip}}</td></tr>
+<tr class="total"><td>Total</td><td class="amount">${{dollars .Amounts.Total}}</td></tr>
+</table>
+</body>
+</html>
+`))
+
+// WriteReceipt writes a printable HTML receipt for a single entry.
+func WriteReceipt(w io.Writer, e Entry) error {
+	return ReceiptTemplate.Execute(w, e)
+}
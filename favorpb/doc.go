@@ -0,0 +1,5 @@
+// Package favorpb holds the protobuf definitions for the Favor API, and the
+// Go code generated from them. The grpcserver package implements the service.
+package favorpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative favor.proto
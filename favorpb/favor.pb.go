@@ -0,0 +1,1576 @@
+// These are the protobuf definitions for the Favor API, for services that
+// would rather speak gRPC than JSON. Like the REST gateway, they smooth over
+// the API's quirks: numbers are numbers, money is in cents, and coordinates
+// come in pairs.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: favor.proto
+
+package favorpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Location is a point on the map.
+type Location struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lat           float64                `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lng           float64                `protobuf:"fixed64,2,opt,name=lng,proto3" json:"lng,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_favor_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Location) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Location) GetLng() float64 {
+	if x != nil {
+		return x.Lng
+	}
+	return 0
+}
+
+// User is a customer or a runner.
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Forename      string                 `protobuf:"bytes,2,opt,name=forename,proto3" json:"forename,omitempty"`
+	Surname       string                 `protobuf:"bytes,3,opt,name=surname,proto3" json:"surname,omitempty"`
+	Phone         string                 `protobuf:"bytes,4,opt,name=phone,proto3" json:"phone,omitempty"`
+	Email         string                 `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_favor_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *User) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *User) GetForename() string {
+	if x != nil {
+		return x.Forename
+	}
+	return ""
+}
+
+func (x *User) GetSurname() string {
+	if x != nil {
+		return x.Surname
+	}
+	return ""
+}
+
+func (x *User) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+// Address is a delivery address.
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Street        string                 `protobuf:"bytes,2,opt,name=street,proto3" json:"street,omitempty"`
+	Zipcode       string                 `protobuf:"bytes,3,opt,name=zipcode,proto3" json:"zipcode,omitempty"`
+	Apartment     string                 `protobuf:"bytes,4,opt,name=apartment,proto3" json:"apartment,omitempty"`
+	Notes         string                 `protobuf:"bytes,5,opt,name=notes,proto3" json:"notes,omitempty"`
+	Location      *Location              `protobuf:"bytes,6,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_favor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Address) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Address) GetStreet() string {
+	if x != nil {
+		return x.Street
+	}
+	return ""
+}
+
+func (x *Address) GetZipcode() string {
+	if x != nil {
+		return x.Zipcode
+	}
+	return ""
+}
+
+func (x *Address) GetApartment() string {
+	if x != nil {
+		return x.Apartment
+	}
+	return ""
+}
+
+func (x *Address) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *Address) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+// Receipt is what a favor cost, in cents.
+type Receipt struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	PaidCents           int64                  `protobuf:"varint,1,opt,name=paid_cents,json=paidCents,proto3" json:"paid_cents,omitempty"`
+	PriceCents          int64                  `protobuf:"varint,2,opt,name=price_cents,json=priceCents,proto3" json:"price_cents,omitempty"`
+	TipCents            int64                  `protobuf:"varint,3,opt,name=tip_cents,json=tipCents,proto3" json:"tip_cents,omitempty"`
+	DeliveryChargeCents int64                  `protobuf:"varint,4,opt,name=delivery_charge_cents,json=deliveryChargeCents,proto3" json:"delivery_charge_cents,omitempty"`
+	CardFeeCents        int64                  `protobuf:"varint,5,opt,name=card_fee_cents,json=cardFeeCents,proto3" json:"card_fee_cents,omitempty"`
+	// total_cents is the price plus the tip, delivery charge and card fee.
+	TotalCents    int64 `protobuf:"varint,6,opt,name=total_cents,json=totalCents,proto3" json:"total_cents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Receipt) Reset() {
+	*x = Receipt{}
+	mi := &file_favor_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Receipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Receipt) ProtoMessage() {}
+
+func (x *Receipt) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Receipt) GetPaidCents() int64 {
+	if x != nil {
+		return x.PaidCents
+	}
+	return 0
+}
+
+func (x *Receipt) GetPriceCents() int64 {
+	if x != nil {
+		return x.PriceCents
+	}
+	return 0
+}
+
+func (x *Receipt) GetTipCents() int64 {
+	if x != nil {
+		return x.TipCents
+	}
+	return 0
+}
+
+func (x *Receipt) GetDeliveryChargeCents() int64 {
+	if x != nil {
+		return x.DeliveryChargeCents
+	}
+	return 0
+}
+
+func (x *Receipt) GetCardFeeCents() int64 {
+	if x != nil {
+		return x.CardFeeCents
+	}
+	return 0
+}
+
+func (x *Receipt) GetTotalCents() int64 {
+	if x != nil {
+		return x.TotalCents
+	}
+	return 0
+}
+
+// Favor is an errand someone asked for.
+type Favor struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title           string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Items           []string               `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Stage           string                 `protobuf:"bytes,4,opt,name=stage,proto3" json:"stage,omitempty"`
+	Status          string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Finished        bool                   `protobuf:"varint,6,opt,name=finished,proto3" json:"finished,omitempty"`
+	MerchantId      string                 `protobuf:"bytes,7,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Customer        *User                  `protobuf:"bytes,9,opt,name=customer,proto3" json:"customer,omitempty"`
+	Runner          *User                  `protobuf:"bytes,10,opt,name=runner,proto3" json:"runner,omitempty"`
+	DeliveryAddress *Address               `protobuf:"bytes,11,opt,name=delivery_address,json=deliveryAddress,proto3" json:"delivery_address,omitempty"`
+	Receipt         *Receipt               `protobuf:"bytes,12,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Favor) Reset() {
+	*x = Favor{}
+	mi := &file_favor_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Favor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Favor) ProtoMessage() {}
+
+func (x *Favor) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Favor.ProtoReflect.Descriptor instead.
+func (*Favor) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Favor) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Favor) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Favor) GetItems() []string {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Favor) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *Favor) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Favor) GetFinished() bool {
+	if x != nil {
+		return x.Finished
+	}
+	return false
+}
+
+func (x *Favor) GetMerchantId() string {
+	if x != nil {
+		return x.MerchantId
+	}
+	return ""
+}
+
+func (x *Favor) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Favor) GetCustomer() *User {
+	if x != nil {
+		return x.Customer
+	}
+	return nil
+}
+
+func (x *Favor) GetRunner() *User {
+	if x != nil {
+		return x.Runner
+	}
+	return nil
+}
+
+func (x *Favor) GetDeliveryAddress() *Address {
+	if x != nil {
+		return x.DeliveryAddress
+	}
+	return nil
+}
+
+func (x *Favor) GetReceipt() *Receipt {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
+// RequestFavor is what it takes to place a favor.
+type RequestFavor struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Title      string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Wants      string                 `protobuf:"bytes,2,opt,name=wants,proto3" json:"wants,omitempty"`
+	Location   *Location              `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	Street     string                 `protobuf:"bytes,4,opt,name=street,proto3" json:"street,omitempty"`
+	Zipcode    string                 `protobuf:"bytes,5,opt,name=zipcode,proto3" json:"zipcode,omitempty"`
+	Apartment  string                 `protobuf:"bytes,6,opt,name=apartment,proto3" json:"apartment,omitempty"`
+	Notes      string                 `protobuf:"bytes,7,opt,name=notes,proto3" json:"notes,omitempty"`
+	MerchantId string                 `protobuf:"bytes,8,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	// market_id is worked out from the location if it's left out.
+	MarketId      string `protobuf:"bytes,9,opt,name=market_id,json=marketId,proto3" json:"market_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestFavor) Reset() {
+	*x = RequestFavor{}
+	mi := &file_favor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestFavor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestFavor) ProtoMessage() {}
+
+func (x *RequestFavor) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestFavor.ProtoReflect.Descriptor instead.
+func (*RequestFavor) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RequestFavor) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *RequestFavor) GetWants() string {
+	if x != nil {
+		return x.Wants
+	}
+	return ""
+}
+
+func (x *RequestFavor) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *RequestFavor) GetStreet() string {
+	if x != nil {
+		return x.Street
+	}
+	return ""
+}
+
+func (x *RequestFavor) GetZipcode() string {
+	if x != nil {
+		return x.Zipcode
+	}
+	return ""
+}
+
+func (x *RequestFavor) GetApartment() string {
+	if x != nil {
+		return x.Apartment
+	}
+	return ""
+}
+
+func (x *RequestFavor) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *RequestFavor) GetMerchantId() string {
+	if x != nil {
+		return x.MerchantId
+	}
+	return ""
+}
+
+func (x *RequestFavor) GetMarketId() string {
+	if x != nil {
+		return x.MarketId
+	}
+	return ""
+}
+
+// Merchant is a place that sells things.
+type Merchant struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Phone       string                 `protobuf:"bytes,3,opt,name=phone,proto3" json:"phone,omitempty"`
+	Address     string                 `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	City        string                 `protobuf:"bytes,5,opt,name=city,proto3" json:"city,omitempty"`
+	State       string                 `protobuf:"bytes,6,opt,name=state,proto3" json:"state,omitempty"`
+	Zipcode     string                 `protobuf:"bytes,7,opt,name=zipcode,proto3" json:"zipcode,omitempty"`
+	Cuisine     string                 `protobuf:"bytes,8,opt,name=cuisine,proto3" json:"cuisine,omitempty"`
+	MarketId    string                 `protobuf:"bytes,9,opt,name=market_id,json=marketId,proto3" json:"market_id,omitempty"`
+	FranchiseId string                 `protobuf:"bytes,10,opt,name=franchise_id,json=franchiseId,proto3" json:"franchise_id,omitempty"`
+	Location    *Location              `protobuf:"bytes,11,opt,name=location,proto3" json:"location,omitempty"`
+	// distance_meters is only set when the merchant was looked up near a point.
+	DistanceMeters *float64 `protobuf:"fixed64,12,opt,name=distance_meters,json=distanceMeters,proto3,oneof" json:"distance_meters,omitempty"`
+	ExpandedMenu   bool     `protobuf:"varint,13,opt,name=expanded_menu,json=expandedMenu,proto3" json:"expanded_menu,omitempty"`
+	CarOnly        bool     `protobuf:"varint,14,opt,name=car_only,json=carOnly,proto3" json:"car_only,omitempty"`
+	// open_now isn't set for merchants without any hours listed.
+	OpenNow       *bool    `protobuf:"varint,15,opt,name=open_now,json=openNow,proto3,oneof" json:"open_now,omitempty"`
+	Hours         []string `protobuf:"bytes,16,rep,name=hours,proto3" json:"hours,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Merchant) Reset() {
+	*x = Merchant{}
+	mi := &file_favor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Merchant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Merchant) ProtoMessage() {}
+
+func (x *Merchant) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Merchant.ProtoReflect.Descriptor instead.
+func (*Merchant) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Merchant) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Merchant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Merchant) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Merchant) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Merchant) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Merchant) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Merchant) GetZipcode() string {
+	if x != nil {
+		return x.Zipcode
+	}
+	return ""
+}
+
+func (x *Merchant) GetCuisine() string {
+	if x != nil {
+		return x.Cuisine
+	}
+	return ""
+}
+
+func (x *Merchant) GetMarketId() string {
+	if x != nil {
+		return x.MarketId
+	}
+	return ""
+}
+
+func (x *Merchant) GetFranchiseId() string {
+	if x != nil {
+		return x.FranchiseId
+	}
+	return ""
+}
+
+func (x *Merchant) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *Merchant) GetDistanceMeters() float64 {
+	if x != nil && x.DistanceMeters != nil {
+		return *x.DistanceMeters
+	}
+	return 0
+}
+
+func (x *Merchant) GetExpandedMenu() bool {
+	if x != nil {
+		return x.ExpandedMenu
+	}
+	return false
+}
+
+func (x *Merchant) GetCarOnly() bool {
+	if x != nil {
+		return x.CarOnly
+	}
+	return false
+}
+
+func (x *Merchant) GetOpenNow() bool {
+	if x != nil && x.OpenNow != nil {
+		return *x.OpenNow
+	}
+	return false
+}
+
+func (x *Merchant) GetHours() []string {
+	if x != nil {
+		return x.Hours
+	}
+	return nil
+}
+
+type ListFavorsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFavorsRequest) Reset() {
+	*x = ListFavorsRequest{}
+	mi := &file_favor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFavorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFavorsRequest) ProtoMessage() {}
+
+func (x *ListFavorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFavorsRequest.ProtoReflect.Descriptor instead.
+func (*ListFavorsRequest) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{7}
+}
+
+type ListFavorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Favors        []*Favor               `protobuf:"bytes,1,rep,name=favors,proto3" json:"favors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFavorsResponse) Reset() {
+	*x = ListFavorsResponse{}
+	mi := &file_favor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFavorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFavorsResponse) ProtoMessage() {}
+
+func (x *ListFavorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFavorsResponse.ProtoReflect.Descriptor instead.
+func (*ListFavorsResponse) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListFavorsResponse) GetFavors() []*Favor {
+	if x != nil {
+		return x.Favors
+	}
+	return nil
+}
+
+type GetFavorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFavorRequest) Reset() {
+	*x = GetFavorRequest{}
+	mi := &file_favor_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFavorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFavorRequest) ProtoMessage() {}
+
+func (x *GetFavorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFavorRequest.ProtoReflect.Descriptor instead.
+func (*GetFavorRequest) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetFavorRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type PlaceFavorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Favor         *RequestFavor          `protobuf:"bytes,1,opt,name=favor,proto3" json:"favor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceFavorRequest) Reset() {
+	*x = PlaceFavorRequest{}
+	mi := &file_favor_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceFavorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceFavorRequest) ProtoMessage() {}
+
+func (x *PlaceFavorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceFavorRequest.ProtoReflect.Descriptor instead.
+func (*PlaceFavorRequest) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PlaceFavorRequest) GetFavor() *RequestFavor {
+	if x != nil {
+		return x.Favor
+	}
+	return nil
+}
+
+type WatchFavorRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// interval is how often to check on the favor. The server decides what's
+	// too often.
+	Interval      *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchFavorRequest) Reset() {
+	*x = WatchFavorRequest{}
+	mi := &file_favor_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchFavorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchFavorRequest) ProtoMessage() {}
+
+func (x *WatchFavorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchFavorRequest.ProtoReflect.Descriptor instead.
+func (*WatchFavorRequest) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WatchFavorRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WatchFavorRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+// FavorUpdate is the favor, and what's different about it since the last
+// update. Every field of the first update is considered changed.
+type FavorUpdate struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Favor          *Favor                 `protobuf:"bytes,1,opt,name=favor,proto3" json:"favor,omitempty"`
+	StageChanged   bool                   `protobuf:"varint,2,opt,name=stage_changed,json=stageChanged,proto3" json:"stage_changed,omitempty"`
+	StatusChanged  bool                   `protobuf:"varint,3,opt,name=status_changed,json=statusChanged,proto3" json:"status_changed,omitempty"`
+	RunnerChanged  bool                   `protobuf:"varint,4,opt,name=runner_changed,json=runnerChanged,proto3" json:"runner_changed,omitempty"`
+	ReceiptChanged bool                   `protobuf:"varint,5,opt,name=receipt_changed,json=receiptChanged,proto3" json:"receipt_changed,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FavorUpdate) Reset() {
+	*x = FavorUpdate{}
+	mi := &file_favor_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FavorUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FavorUpdate) ProtoMessage() {}
+
+func (x *FavorUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FavorUpdate.ProtoReflect.Descriptor instead.
+func (*FavorUpdate) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FavorUpdate) GetFavor() *Favor {
+	if x != nil {
+		return x.Favor
+	}
+	return nil
+}
+
+func (x *FavorUpdate) GetStageChanged() bool {
+	if x != nil {
+		return x.StageChanged
+	}
+	return false
+}
+
+func (x *FavorUpdate) GetStatusChanged() bool {
+	if x != nil {
+		return x.StatusChanged
+	}
+	return false
+}
+
+func (x *FavorUpdate) GetRunnerChanged() bool {
+	if x != nil {
+		return x.RunnerChanged
+	}
+	return false
+}
+
+func (x *FavorUpdate) GetReceiptChanged() bool {
+	if x != nil {
+		return x.ReceiptChanged
+	}
+	return false
+}
+
+type ListMerchantsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Near  *Location              `protobuf:"bytes,1,opt,name=near,proto3" json:"near,omitempty"`
+	// query searches merchant names, forgiving typos, and sorts the results by
+	// how well they match instead of by distance.
+	Query         string `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	OpenOnly      bool   `protobuf:"varint,3,opt,name=open_only,json=openOnly,proto3" json:"open_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMerchantsRequest) Reset() {
+	*x = ListMerchantsRequest{}
+	mi := &file_favor_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMerchantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMerchantsRequest) ProtoMessage() {}
+
+func (x *ListMerchantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMerchantsRequest.ProtoReflect.Descriptor instead.
+func (*ListMerchantsRequest) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListMerchantsRequest) GetNear() *Location {
+	if x != nil {
+		return x.Near
+	}
+	return nil
+}
+
+func (x *ListMerchantsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListMerchantsRequest) GetOpenOnly() bool {
+	if x != nil {
+		return x.OpenOnly
+	}
+	return false
+}
+
+type ListMerchantsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Merchants     []*Merchant            `protobuf:"bytes,1,rep,name=merchants,proto3" json:"merchants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMerchantsResponse) Reset() {
+	*x = ListMerchantsResponse{}
+	mi := &file_favor_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMerchantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMerchantsResponse) ProtoMessage() {}
+
+func (x *ListMerchantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMerchantsResponse.ProtoReflect.Descriptor instead.
+func (*ListMerchantsResponse) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListMerchantsResponse) GetMerchants() []*Merchant {
+	if x != nil {
+		return x.Merchants
+	}
+	return nil
+}
+
+type GetMerchantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMerchantRequest) Reset() {
+	*x = GetMerchantRequest{}
+	mi := &file_favor_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMerchantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMerchantRequest) ProtoMessage() {}
+
+func (x *GetMerchantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMerchantRequest.ProtoReflect.Descriptor instead.
+func (*GetMerchantRequest) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetMerchantRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetMeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMeRequest) Reset() {
+	*x = GetMeRequest{}
+	mi := &file_favor_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMeRequest) ProtoMessage() {}
+
+func (x *GetMeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMeRequest.ProtoReflect.Descriptor instead.
+func (*GetMeRequest) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{16}
+}
+
+type ListAddressesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAddressesRequest) Reset() {
+	*x = ListAddressesRequest{}
+	mi := &file_favor_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAddressesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAddressesRequest) ProtoMessage() {}
+
+func (x *ListAddressesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAddressesRequest.ProtoReflect.Descriptor instead.
+func (*ListAddressesRequest) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{17}
+}
+
+type ListAddressesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Addresses     []*Address             `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAddressesResponse) Reset() {
+	*x = ListAddressesResponse{}
+	mi := &file_favor_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAddressesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAddressesResponse) ProtoMessage() {}
+
+func (x *ListAddressesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAddressesResponse.ProtoReflect.Descriptor instead.
+func (*ListAddressesResponse) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListAddressesResponse) GetAddresses() []*Address {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+type CreateAddressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       *Address               `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAddressRequest) Reset() {
+	*x = CreateAddressRequest{}
+	mi := &file_favor_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAddressRequest) ProtoMessage() {}
+
+func (x *CreateAddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_favor_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAddressRequest.ProtoReflect.Descriptor instead.
+func (*CreateAddressRequest) Descriptor() ([]byte, []int) {
+	return file_favor_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CreateAddressRequest) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+var File_favor_proto protoreflect.FileDescriptor
+
+const file_favor_proto_rawDesc = "" +
+	"\n" +
+	"\vfavor.proto\x12\bfavor.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\".\n" +
+	"\bLocation\x12\x10\n" +
+	"\x03lat\x18\x01 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lng\x18\x02 \x01(\x01R\x03lng\"x\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bforename\x18\x02 \x01(\tR\bforename\x12\x18\n" +
+	"\asurname\x18\x03 \x01(\tR\asurname\x12\x14\n" +
+	"\x05phone\x18\x04 \x01(\tR\x05phone\x12\x14\n" +
+	"\x05email\x18\x05 \x01(\tR\x05email\"\xaf\x01\n" +
+	"\aAddress\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06street\x18\x02 \x01(\tR\x06street\x12\x18\n" +
+	"\azipcode\x18\x03 \x01(\tR\azipcode\x12\x1c\n" +
+	"\tapartment\x18\x04 \x01(\tR\tapartment\x12\x14\n" +
+	"\x05notes\x18\x05 \x01(\tR\x05notes\x12.\n" +
+	"\blocation\x18\x06 \x01(\v2\x12.favor.v1.LocationR\blocation\"\xe1\x01\n" +
+	"\aReceipt\x12\x1d\n" +
+	"\n" +
+	"paid_cents\x18\x01 \x01(\x03R\tpaidCents\x12\x1f\n" +
+	"\vprice_cents\x18\x02 \x01(\x03R\n" +
+	"priceCents\x12\x1b\n" +
+	"\ttip_cents\x18\x03 \x01(\x03R\btipCents\x122\n" +
+	"\x15delivery_charge_cents\x18\x04 \x01(\x03R\x13deliveryChargeCents\x12$\n" +
+	"\x0ecard_fee_cents\x18\x05 \x01(\x03R\fcardFeeCents\x12\x1f\n" +
+	"\vtotal_cents\x18\x06 \x01(\x03R\n" +
+	"totalCents\"\xa8\x03\n" +
+	"\x05Favor\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x14\n" +
+	"\x05items\x18\x03 \x03(\tR\x05items\x12\x14\n" +
+	"\x05stage\x18\x04 \x01(\tR\x05stage\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1a\n" +
+	"\bfinished\x18\x06 \x01(\bR\bfinished\x12\x1f\n" +
+	"\vmerchant_id\x18\a \x01(\tR\n" +
+	"merchantId\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12*\n" +
+	"\bcustomer\x18\t \x01(\v2\x0e.favor.v1.UserR\bcustomer\x12&\n" +
+	"\x06runner\x18\n" +
+	" \x01(\v2\x0e.favor.v1.UserR\x06runner\x12<\n" +
+	"\x10delivery_address\x18\v \x01(\v2\x11.favor.v1.AddressR\x0fdeliveryAddress\x12+\n" +
+	"\areceipt\x18\f \x01(\v2\x11.favor.v1.ReceiptR\areceipt\"\x8e\x02\n" +
+	"\fRequestFavor\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x14\n" +
+	"\x05wants\x18\x02 \x01(\tR\x05wants\x12.\n" +
+	"\blocation\x18\x03 \x01(\v2\x12.favor.v1.LocationR\blocation\x12\x16\n" +
+	"\x06street\x18\x04 \x01(\tR\x06street\x12\x18\n" +
+	"\azipcode\x18\x05 \x01(\tR\azipcode\x12\x1c\n" +
+	"\tapartment\x18\x06 \x01(\tR\tapartment\x12\x14\n" +
+	"\x05notes\x18\a \x01(\tR\x05notes\x12\x1f\n" +
+	"\vmerchant_id\x18\b \x01(\tR\n" +
+	"merchantId\x12\x1b\n" +
+	"\tmarket_id\x18\t \x01(\tR\bmarketId\"\xf1\x03\n" +
+	"\bMerchant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05phone\x18\x03 \x01(\tR\x05phone\x12\x18\n" +
+	"\aaddress\x18\x04 \x01(\tR\aaddress\x12\x12\n" +
+	"\x04city\x18\x05 \x01(\tR\x04city\x12\x14\n" +
+	"\x05state\x18\x06 \x01(\tR\x05state\x12\x18\n" +
+	"\azipcode\x18\a \x01(\tR\azipcode\x12\x18\n" +
+	"\acuisine\x18\b \x01(\tR\acuisine\x12\x1b\n" +
+	"\tmarket_id\x18\t \x01(\tR\bmarketId\x12!\n" +
+	"\ffranchise_id\x18\n" +
+	" \x01(\tR\vfranchiseId\x12.\n" +
+	"\blocation\x18\v \x01(\v2\x12.favor.v1.LocationR\blocation\x12,\n" +
+	"\x0fdistance_meters\x18\f \x01(\x01H\x00R\x0edistanceMeters\x88\x01\x01\x12#\n" +
+	"\rexpanded_menu\x18\r \x01(\bR\fexpandedMenu\x12\x19\n" +
+	"\bcar_only\x18\x0e \x01(\bR\acarOnly\x12\x1e\n" +
+	"\bopen_now\x18\x0f \x01(\bH\x01R\aopenNow\x88\x01\x01\x12\x14\n" +
+	"\x05hours\x18\x10 \x03(\tR\x05hoursB\x12\n" +
+	"\x10_distance_metersB\v\n" +
+	"\t_open_now\"\x13\n" +
+	"\x11ListFavorsRequest\"=\n" +
+	"\x12ListFavorsResponse\x12'\n" +
+	"\x06favors\x18\x01 \x03(\v2\x0f.favor.v1.FavorR\x06favors\"!\n" +
+	"\x0fGetFavorRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"A\n" +
+	"\x11PlaceFavorRequest\x12,\n" +
+	"\x05favor\x18\x01 \x01(\v2\x16.favor.v1.RequestFavorR\x05favor\"Z\n" +
+	"\x11WatchFavorRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\"\xd0\x01\n" +
+	"\vFavorUpdate\x12%\n" +
+	"\x05favor\x18\x01 \x01(\v2\x0f.favor.v1.FavorR\x05favor\x12#\n" +
+	"\rstage_changed\x18\x02 \x01(\bR\fstageChanged\x12%\n" +
+	"\x0estatus_changed\x18\x03 \x01(\bR\rstatusChanged\x12%\n" +
+	"\x0erunner_changed\x18\x04 \x01(\bR\rrunnerChanged\x12'\n" +
+	"\x0freceipt_changed\x18\x05 \x01(\bR\x0ereceiptChanged\"q\n" +
+	"\x14ListMerchantsRequest\x12&\n" +
+	"\x04near\x18\x01 \x01(\v2\x12.favor.v1.LocationR\x04near\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x12\x1b\n" +
+	"\topen_only\x18\x03 \x01(\bR\bopenOnly\"I\n" +
+	"\x15ListMerchantsResponse\x120\n" +
+	"\tmerchants\x18\x01 \x03(\v2\x12.favor.v1.MerchantR\tmerchants\"$\n" +
+	"\x12GetMerchantRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x0e\n" +
+	"\fGetMeRequest\"\x16\n" +
+	"\x14ListAddressesRequest\"H\n" +
+	"\x15ListAddressesResponse\x12/\n" +
+	"\taddresses\x18\x01 \x03(\v2\x11.favor.v1.AddressR\taddresses\"C\n" +
+	"\x14CreateAddressRequest\x12+\n" +
+	"\aaddress\x18\x01 \x01(\v2\x11.favor.v1.AddressR\aaddress2\xe9\x04\n" +
+	"\fFavorService\x12G\n" +
+	"\n" +
+	"ListFavors\x12\x1b.favor.v1.ListFavorsRequest\x1a\x1c.favor.v1.ListFavorsResponse\x126\n" +
+	"\bGetFavor\x12\x19.favor.v1.GetFavorRequest\x1a\x0f.favor.v1.Favor\x12:\n" +
+	"\n" +
+	"PlaceFavor\x12\x1b.favor.v1.PlaceFavorRequest\x1a\x0f.favor.v1.Favor\x12B\n" +
+	"\n" +
+	"WatchFavor\x12\x1b.favor.v1.WatchFavorRequest\x1a\x15.favor.v1.FavorUpdate0\x01\x12P\n" +
+	"\rListMerchants\x12\x1e.favor.v1.ListMerchantsRequest\x1a\x1f.favor.v1.ListMerchantsResponse\x12?\n" +
+	"\vGetMerchant\x12\x1c.favor.v1.GetMerchantRequest\x1a\x12.favor.v1.Merchant\x12/\n" +
+	"\x05GetMe\x12\x16.favor.v1.GetMeRequest\x1a\x0e.favor.v1.User\x12P\n" +
+	"\rListAddresses\x12\x1e.favor.v1.ListAddressesRequest\x1a\x1f.favor.v1.ListAddressesResponse\x12B\n" +
+	"\rCreateAddress\x12\x1e.favor.v1.CreateAddressRequest\x1a\x11.favor.v1.AddressB3Z1github.com/verygoodsoftwarenotvirus/favor/favorpbb\x06proto3"
+
+var (
+	file_favor_proto_rawDescOnce sync.Once
+	file_favor_proto_rawDescData []byte
+)
+
+func file_favor_proto_rawDescGZIP() []byte {
+	file_favor_proto_rawDescOnce.Do(func() {
+		file_favor_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_favor_proto_rawDesc), len(file_favor_proto_rawDesc)))
+	})
+	return file_favor_proto_rawDescData
+}
+
+var file_favor_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_favor_proto_goTypes = []any{
+	(*Location)(nil),              // 0: favor.v1.Location
+	(*User)(nil),                  // 1: favor.v1.User
+	(*Address)(nil),               // 2: favor.v1.Address
+	(*Receipt)(nil),               // 3: favor.v1.Receipt
+	(*Favor)(nil),                 // 4: favor.v1.Favor
+	(*RequestFavor)(nil),          // 5: favor.v1.RequestFavor
+	(*Merchant)(nil),              // 6: favor.v1.Merchant
+	(*ListFavorsRequest)(nil),     // 7: favor.v1.ListFavorsRequest
+	(*ListFavorsResponse)(nil),    // 8: favor.v1.ListFavorsResponse
+	(*GetFavorRequest)(nil),       // 9: favor.v1.GetFavorRequest
+	(*PlaceFavorRequest)(nil),     // 10: favor.v1.PlaceFavorRequest
+	(*WatchFavorRequest)(nil),     // 11: favor.v1.WatchFavorRequest
+	(*FavorUpdate)(nil),           // 12: favor.v1.FavorUpdate
+	(*ListMerchantsRequest)(nil),  // 13: favor.v1.ListMerchantsRequest
+	(*ListMerchantsResponse)(nil), // 14: favor.v1.ListMerchantsResponse
+	(*GetMerchantRequest)(nil),    // 15: favor.v1.GetMerchantRequest
+	(*GetMeRequest)(nil),          // 16: favor.v1.GetMeRequest
+	(*ListAddressesRequest)(nil),  // 17: favor.v1.ListAddressesRequest
+	(*ListAddressesResponse)(nil), // 18: favor.v1.ListAddressesResponse
+	(*CreateAddressRequest)(nil),  // 19: favor.v1.CreateAddressRequest
+	(*timestamppb.Timestamp)(nil), // 20: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 21: google.protobuf.Duration
+}
+var file_favor_proto_depIdxs = []int32{
+	0,  // 0: favor.v1.Address.location:type_name -> favor.v1.Location
+	20, // 1: favor.v1.Favor.created_at:type_name -> google.protobuf.Timestamp
+	1,  // 2: favor.v1.Favor.customer:type_name -> favor.v1.User
+	1,  // 3: favor.v1.Favor.runner:type_name -> favor.v1.User
+	2,  // 4: favor.v1.Favor.delivery_address:type_name -> favor.v1.Address
+	3,  // 5: favor.v1.Favor.receipt:type_name -> favor.v1.Receipt
+	0,  // 6: favor.v1.RequestFavor.location:type_name -> favor.v1.Location
+	0,  // 7: favor.v1.Merchant.location:type_name -> favor.v1.Location
+	4,  // 8: favor.v1.ListFavorsResponse.favors:type_name -> favor.v1.Favor
+	5,  // 9: favor.v1.PlaceFavorRequest.favor:type_name -> favor.v1.RequestFavor
+	21, // 10: favor.v1.WatchFavorRequest.interval:type_name -> google.protobuf.Duration
+	4,  // 11: favor.v1.FavorUpdate.favor:type_name -> favor.v1.Favor
+	0,  // 12: favor.v1.ListMerchantsRequest.near:type_name -> favor.v1.Location
+	6,  // 13: favor.v1.ListMerchantsResponse.merchants:type_name -> favor.v1.Merchant
+	2,  // 14: favor.v1.ListAddressesResponse.addresses:type_name -> favor.v1.Address
+	2,  // 15: favor.v1.CreateAddressRequest.address:type_name -> favor.v1.Address
+	7,  // 16: favor.v1.FavorService.ListFavors:input_type -> favor.v1.ListFavorsRequest
+	9,  // 17: favor.v1.FavorService.GetFavor:input_type -> favor.v1.GetFavorRequest
+	10, // 18: favor.v1.FavorService.PlaceFavor:input_type -> favor.v1.PlaceFavorRequest
+	11, // 19: favor.v1.FavorService.WatchFavor:input_type -> favor.v1.WatchFavorRequest
+	13, // 20: favor.v1.FavorService.ListMerchants:input_type -> favor.v1.ListMerchantsRequest
+	15, // 21: favor.v1.FavorService.GetMerchant:input_type -> favor.v1.GetMerchantRequest
+	16, // 22: favor.v1.FavorService.GetMe:input_type -> favor.v1.GetMeRequest
+	17, // 23: favor.v1.FavorService.ListAddresses:input_type -> favor.v1.ListAddressesRequest
+	19, // 24: favor.v1.FavorService.CreateAddress:input_type -> favor.v1.CreateAddressRequest
+	8,  // 25: favor.v1.FavorService.ListFavors:output_type -> favor.v1.ListFavorsResponse
+	4,  // 26: favor.v1.FavorService.GetFavor:output_type -> favor.v1.Favor
+	4,  // 27: favor.v1.FavorService.PlaceFavor:output_type -> favor.v1.Favor
+	12, // 28: favor.v1.FavorService.WatchFavor:output_type -> favor.v1.FavorUpdate
+	14, // 29: favor.v1.FavorService.ListMerchants:output_type -> favor.v1.ListMerchantsResponse
+	6,  // 30: favor.v1.FavorService.GetMerchant:output_type -> favor.v1.Merchant
+	1,  // 31: favor.v1.FavorService.GetMe:output_type -> favor.v1.User
+	18, // 32: favor.v1.FavorService.ListAddresses:output_type -> favor.v1.ListAddressesResponse
+	2,  // 33: favor.v1.FavorService.CreateAddress:output_type -> favor.v1.Address
+	25, // [25:34] is the sub-list for method output_type
+	16, // [16:25] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_favor_proto_init() }
+func file_favor_proto_init() {
+	if File_favor_proto != nil {
+		return
+	}
+	file_favor_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_favor_proto_rawDesc), len(file_favor_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_favor_proto_goTypes,
+		DependencyIndexes: file_favor_proto_depIdxs,
+		MessageInfos:      file_favor_proto_msgTypes,
+	}.Build()
+	File_favor_proto = out.File
+	file_favor_proto_goTypes = nil
+	file_favor_proto_depIdxs = nil
+}
@@ -0,0 +1,446 @@
+// These are the protobuf definitions for the Favor API, for services that
+// would rather speak gRPC than JSON. Like the REST gateway, they smooth over
+// the API's quirks: numbers are numbers, money is in cents, and coordinates
+// come in pairs.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: favor.proto
+
+package favorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FavorService_ListFavors_FullMethodName    = "/favor.v1.FavorService/ListFavors"
+	FavorService_GetFavor_FullMethodName      = "/favor.v1.FavorService/GetFavor"
+	FavorService_PlaceFavor_FullMethodName    = "/favor.v1.FavorService/PlaceFavor"
+	FavorService_WatchFavor_FullMethodName    = "/favor.v1.FavorService/WatchFavor"
+	FavorService_ListMerchants_FullMethodName = "/favor.v1.FavorService/ListMerchants"
+	FavorService_GetMerchant_FullMethodName   = "/favor.v1.FavorService/GetMerchant"
+	FavorService_GetMe_FullMethodName         = "/favor.v1.FavorService/GetMe"
+	FavorService_ListAddresses_FullMethodName = "/favor.v1.FavorService/ListAddresses"
+	FavorService_CreateAddress_FullMethodName = "/favor.v1.FavorService/CreateAddress"
+)
+
+// FavorServiceClient is the client API for FavorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FavorService is the Favor API. Callers authenticate by sending their API key
+// as "authorization: Bearer <key>" or "x-api-key: <key>" metadata.
+type FavorServiceClient interface {
+	ListFavors(ctx context.Context, in *ListFavorsRequest, opts ...grpc.CallOption) (*ListFavorsResponse, error)
+	GetFavor(ctx context.Context, in *GetFavorRequest, opts ...grpc.CallOption) (*Favor, error)
+	PlaceFavor(ctx context.Context, in *PlaceFavorRequest, opts ...grpc.CallOption) (*Favor, error)
+	// WatchFavor sends the favor as it is now, and again every time its stage,
+	// status, runner or receipt changes. The stream ends once the favor is
+	// finished.
+	WatchFavor(ctx context.Context, in *WatchFavorRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FavorUpdate], error)
+	ListMerchants(ctx context.Context, in *ListMerchantsRequest, opts ...grpc.CallOption) (*ListMerchantsResponse, error)
+	GetMerchant(ctx context.Context, in *GetMerchantRequest, opts ...grpc.CallOption) (*Merchant, error)
+	GetMe(ctx context.Context, in *GetMeRequest, opts ...grpc.CallOption) (*User, error)
+	ListAddresses(ctx context.Context, in *ListAddressesRequest, opts ...grpc.CallOption) (*ListAddressesResponse, error)
+	CreateAddress(ctx context.Context, in *CreateAddressRequest, opts ...grpc.CallOption) (*Address, error)
+}
+
+type favorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFavorServiceClient(cc grpc.ClientConnInterface) FavorServiceClient {
+	return &favorServiceClient{cc}
+}
+
+func (c *favorServiceClient) ListFavors(ctx context.Context, in *ListFavorsRequest, opts ...grpc.CallOption) (*ListFavorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFavorsResponse)
+	err := c.cc.Invoke(ctx, FavorService_ListFavors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favorServiceClient) GetFavor(ctx context.Context, in *GetFavorRequest, opts ...grpc.CallOption) (*Favor, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Favor)
+	err := c.cc.Invoke(ctx, FavorService_GetFavor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favorServiceClient) PlaceFavor(ctx context.Context, in *PlaceFavorRequest, opts ...grpc.CallOption) (*Favor, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Favor)
+	err := c.cc.Invoke(ctx, FavorService_PlaceFavor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favorServiceClient) WatchFavor(ctx context.Context, in *WatchFavorRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FavorUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FavorService_ServiceDesc.Streams[0], FavorService_WatchFavor_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchFavorRequest, FavorUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FavorService_WatchFavorClient = grpc.ServerStreamingClient[FavorUpdate]
+
+func (c *favorServiceClient) ListMerchants(ctx context.Context, in *ListMerchantsRequest, opts ...grpc.CallOption) (*ListMerchantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMerchantsResponse)
+	err := c.cc.Invoke(ctx, FavorService_ListMerchants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favorServiceClient) GetMerchant(ctx context.Context, in *GetMerchantRequest, opts ...grpc.CallOption) (*Merchant, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Merchant)
+	err := c.cc.Invoke(ctx, FavorService_GetMerchant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favorServiceClient) GetMe(ctx context.Context, in *GetMeRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, FavorService_GetMe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favorServiceClient) ListAddresses(ctx context.Context, in *ListAddressesRequest, opts ...grpc.CallOption) (*ListAddressesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAddressesResponse)
+	err := c.cc.Invoke(ctx, FavorService_ListAddresses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *favorServiceClient) CreateAddress(ctx context.Context, in *CreateAddressRequest, opts ...grpc.CallOption) (*Address, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Address)
+	err := c.cc.Invoke(ctx, FavorService_CreateAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FavorServiceServer is the server API for FavorService service.
+// All implementations must embed UnimplementedFavorServiceServer
+// for forward compatibility.
+//
+// FavorService is the Favor API. Callers authenticate by sending their API key
+// as "authorization: Bearer <key>" or "x-api-key: <key>" metadata.
+type FavorServiceServer interface {
+	ListFavors(context.Context, *ListFavorsRequest) (*ListFavorsResponse, error)
+	GetFavor(context.Context, *GetFavorRequest) (*Favor, error)
+	PlaceFavor(context.Context, *PlaceFavorRequest) (*Favor, error)
+	// WatchFavor sends the favor as it is now, and again every time its stage,
+	// status, runner or receipt changes. The stream ends once the favor is
+	// finished.
+	WatchFavor(*WatchFavorRequest, grpc.ServerStreamingServer[FavorUpdate]) error
+	ListMerchants(context.Context, *ListMerchantsRequest) (*ListMerchantsResponse, error)
+	GetMerchant(context.Context, *GetMerchantRequest) (*Merchant, error)
+	GetMe(context.Context, *GetMeRequest) (*User, error)
+	ListAddresses(context.Context, *ListAddressesRequest) (*ListAddressesResponse, error)
+	CreateAddress(context.Context, *CreateAddressRequest) (*Address, error)
+	mustEmbedUnimplementedFavorServiceServer()
+}
+
+// UnimplementedFavorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFavorServiceServer struct{}
+
+func (UnimplementedFavorServiceServer) ListFavors(context.Context, *ListFavorsRequest) (*ListFavorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFavors not implemented")
+}
+func (UnimplementedFavorServiceServer) GetFavor(context.Context, *GetFavorRequest) (*Favor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFavor not implemented")
+}
+func (UnimplementedFavorServiceServer) PlaceFavor(context.Context, *PlaceFavorRequest) (*Favor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceFavor not implemented")
+}
+func (UnimplementedFavorServiceServer) WatchFavor(*WatchFavorRequest, grpc.ServerStreamingServer[FavorUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchFavor not implemented")
+}
+func (UnimplementedFavorServiceServer) ListMerchants(context.Context, *ListMerchantsRequest) (*ListMerchantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMerchants not implemented")
+}
+func (UnimplementedFavorServiceServer) GetMerchant(context.Context, *GetMerchantRequest) (*Merchant, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMerchant not implemented")
+}
+func (UnimplementedFavorServiceServer) GetMe(context.Context, *GetMeRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMe not implemented")
+}
+func (UnimplementedFavorServiceServer) ListAddresses(context.Context, *ListAddressesRequest) (*ListAddressesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAddresses not implemented")
+}
+func (UnimplementedFavorServiceServer) CreateAddress(context.Context, *CreateAddressRequest) (*Address, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAddress not implemented")
+}
+func (UnimplementedFavorServiceServer) mustEmbedUnimplementedFavorServiceServer() {}
+func (UnimplementedFavorServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeFavorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FavorServiceServer will
+// result in compilation errors.
+type UnsafeFavorServiceServer interface {
+	mustEmbedUnimplementedFavorServiceServer()
+}
+
+func RegisterFavorServiceServer(s grpc.ServiceRegistrar, srv FavorServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFavorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FavorService_ServiceDesc, srv)
+}
+
+func _FavorService_ListFavors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFavorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavorServiceServer).ListFavors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavorService_ListFavors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavorServiceServer).ListFavors(ctx, req.(*ListFavorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavorService_GetFavor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFavorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavorServiceServer).GetFavor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavorService_GetFavor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavorServiceServer).GetFavor(ctx, req.(*GetFavorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavorService_PlaceFavor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceFavorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavorServiceServer).PlaceFavor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavorService_PlaceFavor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavorServiceServer).PlaceFavor(ctx, req.(*PlaceFavorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavorService_WatchFavor_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchFavorRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FavorServiceServer).WatchFavor(m, &grpc.GenericServerStream[WatchFavorRequest, FavorUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FavorService_WatchFavorServer = grpc.ServerStreamingServer[FavorUpdate]
+
+func _FavorService_ListMerchants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMerchantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavorServiceServer).ListMerchants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavorService_ListMerchants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavorServiceServer).ListMerchants(ctx, req.(*ListMerchantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavorService_GetMerchant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMerchantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavorServiceServer).GetMerchant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavorService_GetMerchant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavorServiceServer).GetMerchant(ctx, req.(*GetMerchantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavorService_GetMe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavorServiceServer).GetMe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavorService_GetMe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavorServiceServer).GetMe(ctx, req.(*GetMeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavorService_ListAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavorServiceServer).ListAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavorService_ListAddresses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavorServiceServer).ListAddresses(ctx, req.(*ListAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FavorService_CreateAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FavorServiceServer).CreateAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FavorService_CreateAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FavorServiceServer).CreateAddress(ctx, req.(*CreateAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FavorService_ServiceDesc is the grpc.ServiceDesc for FavorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FavorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "favor.v1.FavorService",
+	HandlerType: (*FavorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListFavors",
+			Handler:    _FavorService_ListFavors_Handler,
+		},
+		{
+			MethodName: "GetFavor",
+			Handler:    _FavorService_GetFavor_Handler,
+		},
+		{
+			MethodName: "PlaceFavor",
+			Handler:    _FavorService_PlaceFavor_Handler,
+		},
+		{
+			MethodName: "ListMerchants",
+			Handler:    _FavorService_ListMerchants_Handler,
+		},
+		{
+			MethodName: "GetMerchant",
+			Handler:    _FavorService_GetMerchant_Handler,
+		},
+		{
+			MethodName: "GetMe",
+			Handler:    _FavorService_GetMe_Handler,
+		},
+		{
+			MethodName: "ListAddresses",
+			Handler:    _FavorService_ListAddresses_Handler,
+		},
+		{
+			MethodName: "CreateAddress",
+			Handler:    _FavorService_CreateAddress_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchFavor",
+			Handler:       _FavorService_WatchFavor_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "favor.proto",
+}
@@ -3,8 +3,10 @@ package favor
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -35,6 +37,81 @@ type Receipt struct {
 	RebatePrice    string `json:"rebate_price"`
 }
 
+// ReceiptAmounts is a Receipt with all of its dollar strings converted into
+// cents, so that we can do math with them without floating point nonsense.
+type ReceiptAmounts struct {
+	Paid           int64
+	Price          int64
+	Tip            int64
+	SuggestedTip   int64
+	MinimumTip     int64
+	DeliveryCharge int64
+	CcFeeAmount    int64
+	RebatePrice    int64
+}
+
+// Fees returns the sum of everything on the receipt that isn't the actual
+// price of the items ordered.
+func (ra ReceiptAmounts) Fees() int64 {
+	return ra.Tip + ra.DeliveryCharge + ra.CcFeeAmount
+}
+
+// Total returns the price of the items ordered plus all of the fees.
+func (ra ReceiptAmounts) Total() int64 {
+	return ra.Price + ra.Fees()
+}
+
+// parseAmount converts a dollar string like "12.34" or "$12.34" into cents.
+// Empty strings are treated as zero, since the API omits values that don't apply.
+func parseAmount(s string) (int64, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "$")
+	if s == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Error parsing amount %v into float!:\n%v", s, err)
+	}
+	return int64(math.Round(f * 100)), nil
+}
+
+// FormatCents renders an amount of cents as a dollar string, like "12.34",
+// which is how the API writes them, and what spreadsheets expect. Tack on your
+// own dollar sign if you want one.
+func FormatCents(c int64) string {
+	sign := ""
+	if c < 0 {
+		sign = "-"
+		c = -c
+	}
+	return fmt.Sprintf("%v%d.%02d", sign, c/100, c%100)
+}
+
+// Amounts converts the receipt's dollar strings into cents.
+func (r Receipt) Amounts() (ReceiptAmounts, error) {
+	ra := ReceiptAmounts{MinimumTip: int64(r.MinimumTip) * 100}
+	fields := []struct {
+		value string
+		dest  *int64
+	}{
+		{r.Paid, &ra.Paid},
+		{r.Price, &ra.Price},
+		{r.Tip, &ra.Tip},
+		{r.SuggestedTip, &ra.SuggestedTip},
+		{r.DeliveryCharge, &ra.DeliveryCharge},
+		{r.CcFeeAmount, &ra.CcFeeAmount},
+		{r.RebatePrice, &ra.RebatePrice},
+	}
+	for _, f := range fields {
+		amount, err := parseAmount(f.value)
+		if err != nil {
+			return ReceiptAmounts{}, err
+		}
+		*f.dest = amount
+	}
+	return ra, nil
+}
+
 // RequestFavor represents what we need to send to the Favor server to place a Favor
 type RequestFavor struct {
 	Title            string  `json:"title"`
@@ -128,8 +205,30 @@ func (rf RequestFavor) CreateFormString() url.Values {
 	return u
 }
 
-// PlaceFavor places a Favor order with the Favor API
+// PlaceFavor places a Favor order with the Favor API. If the client has a
+// Geofence, the request is validated against it before anything is sent, and
+// a missing MarketID is filled in from it. If that doesn't settle it, we look
+// the market up from the delivery coordinates, if there are any, and refuse
+// with a NoMarketError if nobody delivers there.
 func (c Client) PlaceFavor(rf RequestFavor) (Favor, error) {
+	if c.Geofence != nil {
+		if marketID, ok := c.Geofence.MarketAt(rf.Location()); ok && rf.MarketID == 0 {
+			rf.MarketID, _ = strconv.Atoi(marketID)
+		}
+		if err := rf.Validate(c.Geofence); err != nil {
+			return Favor{}, err
+		}
+	}
+	if rf.MarketID == 0 && (rf.Lat != 0 || rf.Lng != 0) {
+		markets, err := c.GetMarkets()
+		if err != nil {
+			return Favor{}, err
+		}
+		if rf, err = rf.WithMarket(markets); err != nil {
+			return Favor{}, err
+		}
+	}
+
 	requestBody := rf.CreateFormString()
 	uri := c.BuildURL("favors/", map[string]string{})
 	responseData, err := c.makeAPIRequestWithBody("post", uri, requestBody)
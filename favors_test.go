@@ -24,3 +24,10 @@ func TestCreateFormString(t *testing.T) {
 	actual := x.CreateFormString()
 	assert.Equal(t, expected, actual)
 }
+
+func TestFormatCents(t *testing.T) {
+	assert.Equal(t, "12.34", FormatCents(1234))
+	assert.Equal(t, "0.05", FormatCents(5))
+	assert.Equal(t, "0.00", FormatCents(0))
+	assert.Equal(t, "-1.50", FormatCents(-150))
+}
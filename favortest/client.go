@@ -0,0 +1,34 @@
+package favortest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// Token is the token NewClient's clients are made with. It's the right length,
+// and that's all that can be said for it.
+const Token = "thisisarandomstringfortestinglol"
+
+// NewClient returns a client whose every request goes to handler instead of
+// the Favor API, no matter where it was meant for. The pretend API is shut
+// down when the test is done.
+func NewClient(t testing.TB, handler http.Handler) *favor.Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := favor.New(Token)
+	if err != nil {
+		t.Fatalf("Constructor failed with the following error: %v", err)
+	}
+	c.Secure = false
+	c.Client = http.Client{Transport: &http.Transport{
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			return url.Parse(server.URL)
+		},
+	}}
+	return c
+}
@@ -0,0 +1,63 @@
+// Package favortest builds favors and clients for tests, so that every package
+// that needs a few favors lying around doesn't end up with its own fixture
+// function whose arguments you have to count to read, or its own copy of the
+// plumbing it takes to point a client at a pretend Favor API.
+package favortest
+
+import (
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// These are what every paid favor gets charged on top of its price and tip,
+// unless the test says otherwise.
+const (
+	DeliveryCharge = "5.00"
+	CcFeeAmount    = "0.50"
+)
+
+// Spec is the parts of a favor tests usually care about. Anything left empty
+// is left empty in the favor, except that a favor with a Price or a Tip gets
+// a receipt with the usual DeliveryCharge and CcFeeAmount, like a real one.
+type Spec struct {
+	ID         string
+	Title      string
+	Stage      string
+	MerchantID string
+	Customer   favor.User
+	Created    time.Time
+	Items      []string
+	Address    favor.Address
+	Price      string
+	Tip        string
+}
+
+// Favor builds the favor.
+func (s Spec) Favor() favor.Favor {
+	f := favor.Favor{
+		ID:              s.ID,
+		Title:           s.Title,
+		Stage:           s.Stage,
+		MerchantID:      s.MerchantID,
+		Customer:        s.Customer,
+		Items:           s.Items,
+		DeliveryAddress: s.Address,
+	}
+	if !s.Created.IsZero() {
+		f.CreatedAt = int(s.Created.Unix())
+	}
+	if s.Price != "" || s.Tip != "" {
+		f.Receipt = favor.Receipt{Price: s.Price, Tip: s.Tip, DeliveryCharge: DeliveryCharge, CcFeeAmount: CcFeeAmount}
+	}
+	return f
+}
+
+// Favors builds a favor for each spec.
+func Favors(specs ...Spec) []favor.Favor {
+	favors := []favor.Favor{}
+	for _, s := range specs {
+		favors = append(favors, s.Favor())
+	}
+	return favors
+}
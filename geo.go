@@ -0,0 +1,149 @@
+package favor
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// earthRadius is the mean radius of the Earth, in meters.
+const earthRadius = 6371008.8
+
+// LatLng is a point on the globe. Coordinates show up as floats in some places
+// and strings in others in the Favor API, so this gives us one thing to
+// convert everything into before doing any math.
+type LatLng struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// ParseLatLng converts a pair of coordinate strings, like the ones found on
+// Merchants and Addresses, into a LatLng.
+func ParseLatLng(lat, lng string) (LatLng, error) {
+	la, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil {
+		return LatLng{}, fmt.Errorf("Error parsing latitude %v into float!:\n%v", lat, err)
+	}
+	ln, err := strconv.ParseFloat(strings.TrimSpace(lng), 64)
+	if err != nil {
+		return LatLng{}, fmt.Errorf("Error parsing longitude %v into float!:\n%v", lng, err)
+	}
+	p := LatLng{Lat: la, Lng: ln}
+	return p, p.Validate()
+}
+
+// Validate returns an error if the point isn't somewhere on Earth.
+func (p LatLng) Validate() error {
+	if math.IsNaN(p.Lat) || p.Lat < -90 || p.Lat > 90 {
+		return fmt.Errorf("Latitude %v is out of range, it must be between -90 and 90.", p.Lat)
+	}
+	if math.IsNaN(p.Lng) || p.Lng < -180 || p.Lng > 180 {
+		return fmt.Errorf("Longitude %v is out of range, it must be between -180 and 180.", p.Lng)
+	}
+	return nil
+}
+
+// String renders the point as "lat,lng"
+func (p LatLng) String() string {
+	return fmt.Sprintf("%v,%v", strconv.FormatFloat(p.Lat, 'f', -1, 64), strconv.FormatFloat(p.Lng, 'f', -1, 64))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
+
+// DistanceTo returns the great circle distance between two points in meters,
+// as calculated by the haversine formula.
+func (p LatLng) DistanceTo(other LatLng) float64 {
+	lat1, lat2 := radians(p.Lat), radians(other.Lat)
+	dLat := lat2 - lat1
+	dLng := radians(other.Lng - p.Lng)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return earthRadius * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// BearingTo returns the initial compass bearing from one point to another, in
+// degrees clockwise from north.
+func (p LatLng) BearingTo(other LatLng) float64 {
+	lat1, lat2 := radians(p.Lat), radians(other.Lat)
+	dLng := radians(other.Lng - p.Lng)
+
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// BoundingBox is a rectangle of coordinates, described by its south west and
+// north east corners.
+type BoundingBox struct {
+	SouthWest LatLng `json:"south_west"`
+	NorthEast LatLng `json:"north_east"`
+}
+
+// BoundingBoxAround returns the smallest BoundingBox containing every point
+// within radius meters of center.
+func BoundingBoxAround(center LatLng, radius float64) BoundingBox {
+	dLat := degrees(radius / earthRadius)
+	dLng := degrees(radius / (earthRadius * math.Cos(radians(center.Lat))))
+	if math.IsInf(dLng, 0) || math.IsNaN(dLng) || dLng > 180 {
+		dLng = 180
+	}
+	return BoundingBox{
+		SouthWest: LatLng{Lat: math.Max(center.Lat-dLat, -90), Lng: math.Max(center.Lng-dLng, -180)},
+		NorthEast: LatLng{Lat: math.Min(center.Lat+dLat, 90), Lng: math.Min(center.Lng+dLng, 180)},
+	}
+}
+
+// Contains returns whether the point is within the box.
+func (b BoundingBox) Contains(p LatLng) bool {
+	return p.Lat >= b.SouthWest.Lat && p.Lat <= b.NorthEast.Lat && p.Lng >= b.SouthWest.Lng && p.Lng <= b.NorthEast.Lng
+}
+
+// Center returns the point in the middle of the box.
+func (b BoundingBox) Center() LatLng {
+	return LatLng{Lat: (b.SouthWest.Lat + b.NorthEast.Lat) / 2, Lng: (b.SouthWest.Lng + b.NorthEast.Lng) / 2}
+}
+
+// Location returns the merchant's coordinates as a LatLng.
+func (m Merchant) Location() (LatLng, error) {
+	return ParseLatLng(m.Lat, m.Lng)
+}
+
+// Location returns the address's coordinates as a LatLng.
+func (a Address) Location() (LatLng, error) {
+	return ParseLatLng(a.Lat, a.Lng)
+}
+
+// Location returns the request's delivery coordinates as a LatLng.
+func (rf RequestFavor) Location() LatLng {
+	return LatLng{Lat: rf.Lat, Lng: rf.Lng}
+}
+
+// DistanceFrom returns how far the merchant is from a point, in meters. This is
+// useful for sanity checking the Distance the server gives us, which is only
+// as good as whatever location the server thinks we're at.
+func (m Merchant) DistanceFrom(p LatLng) (float64, error) {
+	loc, err := m.Location()
+	if err != nil {
+		return 0, err
+	}
+	return p.DistanceTo(loc), nil
+}
+
+// SortByDistance returns a copy of the merchants, sorted by how close they are
+// to a point. Merchants without usable coordinates go at the end, by name.
+func (m Merchants) SortByDistance(from LatLng) Merchants {
+	return m.SortBy(ByDistance(from), ByName())
+}
+
+// WithinRadius returns the merchants that are no more than meters away from a
+// point. Merchants without usable coordinates are left out.
+func (m Merchants) WithinRadius(from LatLng, meters float64) Merchants {
+	return m.Filter(WithinMeters(from, meters))
+}
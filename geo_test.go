@@ -0,0 +1,64 @@
+package favor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	austin     = LatLng{Lat: 30.2672, Lng: -97.7431}
+	dallas     = LatLng{Lat: 32.7767, Lng: -96.7970}
+	sydneyOpry = LatLng{Lat: -33.8568, Lng: 151.2153}
+)
+
+func TestParseLatLng(t *testing.T) {
+	p, err := ParseLatLng("-33.865143", " 151.209900")
+	assert.Nil(t, err)
+	assert.Equal(t, LatLng{Lat: -33.865143, Lng: 151.2099}, p)
+
+	_, err = ParseLatLng("north", "151.2")
+	assert.NotNil(t, err)
+	_, err = ParseLatLng("91", "151.2")
+	assert.NotNil(t, err, "Latitudes above 90 shouldn't validate")
+}
+
+func TestDistanceAndBearing(t *testing.T) {
+	// Austin to Dallas is roughly 293 kilometers as the crow flies.
+	assert.InDelta(t, 293000, austin.DistanceTo(dallas), 2000)
+	assert.InDelta(t, 0, austin.DistanceTo(austin), 0.0001)
+	assert.InDelta(t, 17, austin.BearingTo(dallas), 1)
+	assert.InDelta(t, 90, LatLng{0, 0}.BearingTo(LatLng{0, 1}), 0.0001)
+}
+
+func TestBoundingBoxAround(t *testing.T) {
+	b := BoundingBoxAround(austin, 1000)
+	assert.True(t, b.Contains(austin))
+	assert.True(t, b.Contains(LatLng{Lat: austin.Lat + 0.008, Lng: austin.Lng}))
+	assert.False(t, b.Contains(LatLng{Lat: austin.Lat + 0.01, Lng: austin.Lng}))
+	assert.InDelta(t, austin.Lat, b.Center().Lat, 0.0001)
+}
+
+func TestMerchantsByDistance(t *testing.T) {
+	ms := Merchants{
+		Merchant{ID: "1", Name: "Sydney", Lat: "-33.865143", Lng: "151.209900"},
+		Merchant{ID: "2", Name: "Nowhere"},
+		Merchant{ID: "3", Name: "Dallas", Lat: "32.7767", Lng: "-96.7970"},
+		Merchant{ID: "4", Name: "Austin", Lat: "30.2672", Lng: "-97.7431"},
+	}
+
+	sorted := ms.SortByDistance(austin)
+	names := []string{}
+	for _, m := range sorted {
+		names = append(names, m.Name)
+	}
+	assert.Equal(t, []string{"Austin", "Dallas", "Sydney", "Nowhere"}, names)
+	assert.Equal(t, "Sydney", ms[0].Name, "SortByDistance shouldn't modify the original slice")
+
+	within := ms.WithinRadius(austin, 300000)
+	assert.Len(t, within, 2)
+
+	d, err := ms[0].DistanceFrom(sydneyOpry)
+	assert.Nil(t, err)
+	assert.True(t, d < 2000)
+}
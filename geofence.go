@@ -0,0 +1,213 @@
+package favor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Boundary is the area a market delivers to. The first ring is the outline of
+// the area, and any rings after that are holes cut out of it, which is how
+// GeoJSON does things. A market with more than one disconnected area gets more
+// than one Boundary.
+type Boundary struct {
+	MarketID string
+	Rings    [][]LatLng
+}
+
+// ringContains uses the ray casting algorithm to figure out if a point is
+// inside a ring, treating coordinates as flat, which is close enough at the
+// scale of a city.
+func ringContains(ring []LatLng, p LatLng) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) {
+			crossing := (b.Lng-a.Lng)*(p.Lat-a.Lat)/(b.Lat-a.Lat) + a.Lng
+			if p.Lng < crossing {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// Contains returns whether the point is inside the boundary, and not in one of its holes.
+func (b Boundary) Contains(p LatLng) bool {
+	if len(b.Rings) == 0 || !ringContains(b.Rings[0], p) {
+		return false
+	}
+	for _, hole := range b.Rings[1:] {
+		if ringContains(hole, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// Geofence is the collection of every market's delivery area.
+type Geofence struct {
+	Boundaries []Boundary
+}
+
+// MarketAt returns the ID of the market whose boundary contains the point.
+func (g Geofence) MarketAt(p LatLng) (string, bool) {
+	for _, b := range g.Boundaries {
+		if b.Contains(p) {
+			return b.MarketID, true
+		}
+	}
+	return "", false
+}
+
+// Contains returns whether the point is deliverable to at all.
+func (g Geofence) Contains(p LatLng) bool {
+	_, ok := g.MarketAt(p)
+	return ok
+}
+
+type geoJSONFeatureCollection struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Properties map[string]interface{} `json:"properties"`
+		Geometry   struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// geoJSONRings converts GeoJSON's [lng, lat] pairs into LatLngs.
+func geoJSONRings(rings [][][]float64) ([][]LatLng, error) {
+	converted := [][]LatLng{}
+	for _, ring := range rings {
+		points := []LatLng{}
+		for _, position := range ring {
+			if len(position) < 2 {
+				return nil, fmt.Errorf("GeoJSON positions must have at least two coordinates, got %v.", position)
+			}
+			p := LatLng{Lat: position[1], Lng: position[0]}
+			if err := p.Validate(); err != nil {
+				return nil, err
+			}
+			points = append(points, p)
+		}
+		converted = append(converted, points)
+	}
+	return converted, nil
+}
+
+// ParseGeofence reads market boundaries from a GeoJSON FeatureCollection. Each
+// feature must be a Polygon or MultiPolygon, with the market it belongs to in
+// its market_id property.
+func ParseGeofence(r io.Reader) (Geofence, error) {
+	fc := geoJSONFeatureCollection{}
+	d := json.NewDecoder(r)
+	// Otherwise numeric market IDs come back as float64s, and print as 1e+06.
+	d.UseNumber()
+	if err := d.Decode(&fc); err != nil {
+		return Geofence{}, err
+	}
+	if fc.Type != "FeatureCollection" {
+		return Geofence{}, fmt.Errorf("Expected a GeoJSON FeatureCollection, got %q.", fc.Type)
+	}
+
+	g := Geofence{}
+	for i, f := range fc.Features {
+		marketID := strings.TrimSpace(fmt.Sprint(f.Properties["market_id"]))
+		if f.Properties["market_id"] == nil || marketID == "" {
+			return Geofence{}, fmt.Errorf("Feature %v has no market_id property.", i)
+		}
+
+		polygons := [][][][]float64{}
+		switch f.Geometry.Type {
+		case "Polygon":
+			polygon := [][][]float64{}
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polygon); err != nil {
+				return Geofence{}, err
+			}
+			polygons = append(polygons, polygon)
+		case "MultiPolygon":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polygons); err != nil {
+				return Geofence{}, err
+			}
+		default:
+			return Geofence{}, fmt.Errorf("Feature %v is a %v, only Polygons and MultiPolygons are supported.", i, f.Geometry.Type)
+		}
+
+		for _, polygon := range polygons {
+			rings, err := geoJSONRings(polygon)
+			if err != nil {
+				return Geofence{}, err
+			}
+			g.Boundaries = append(g.Boundaries, Boundary{MarketID: marketID, Rings: rings})
+		}
+	}
+	return g, nil
+}
+
+// LoadGeofenceFile reads market boundaries from a GeoJSON file on disk, for
+// when you'd rather bundle the boundaries than ask the API for them.
+func LoadGeofenceFile(path string) (Geofence, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Geofence{}, err
+	}
+	defer f.Close()
+	return ParseGeofence(f)
+}
+
+// GetGeofence is used to retrieve every market's boundaries from the Favor API.
+func (c Client) GetGeofence() (Geofence, error) {
+	uri := c.BuildURL("markets/boundaries", map[string]string{})
+	boundaryData, err := c.makeAPIRequest("get", uri)
+	if err != nil {
+		return Geofence{}, err
+	}
+	return ParseGeofence(bytes.NewReader(boundaryData))
+}
+
+// Validate checks that a RequestFavor has everything the server is going to
+// want, and if a Geofence is provided, that the delivery address is somewhere
+// Favor actually delivers to, and in the market the request says it is.
+func (rf RequestFavor) Validate(g *Geofence) error {
+	missing := []string{}
+	if strings.TrimSpace(rf.Title) == "" {
+		missing = append(missing, "title")
+	}
+	if strings.TrimSpace(rf.Wants) == "" {
+		missing = append(missing, "wants")
+	}
+	if strings.TrimSpace(rf.Street) == "" {
+		missing = append(missing, "street")
+	}
+	if strings.TrimSpace(rf.Zipcode) == "" {
+		missing = append(missing, "zipcode")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("The favor request is missing required fields: %v", strings.Join(missing, ", "))
+	}
+
+	p := rf.Location()
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if p.Lat == 0 && p.Lng == 0 {
+		return fmt.Errorf("The favor request has no delivery coordinates.")
+	}
+
+	if g == nil {
+		return nil
+	}
+	marketID, ok := g.MarketAt(p)
+	if !ok {
+		return fmt.Errorf("Favor doesn't deliver to %v, it's outside of every market.", p)
+	}
+	if rf.MarketID != 0 && fmt.Sprint(rf.MarketID) != marketID {
+		return fmt.Errorf("The delivery address is in market %v, but the request says it's in market %v.", marketID, rf.MarketID)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package favor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A square around downtown Austin with a hole in the middle of it, and two
+// little squares in Dallas.
+var dummyGeofenceJSON = `
+{
+	"type": "FeatureCollection",
+	"features": [
+		{
+			"type": "Feature",
+			"properties": {"market_id": 1},
+			"geometry": {
+				"type": "Polygon",
+				"coordinates": [
+					[[-98.0, 30.0], [-97.5, 30.0], [-97.5, 30.5], [-98.0, 30.5], [-98.0, 30.0]],
+					[[-97.76, 30.24], [-97.74, 30.24], [-97.74, 30.26], [-97.76, 30.26], [-97.76, 30.24]]
+				]
+			}
+		},
+		{
+			"type": "Feature",
+			"properties": {"market_id": "2"},
+			"geometry": {
+				"type": "MultiPolygon",
+				"coordinates": [
+					[[[-97.0, 32.5], [-96.5, 32.5], [-96.5, 33.0], [-97.0, 33.0], [-97.0, 32.5]]],
+					[[[-96.4, 32.5], [-96.3, 32.5], [-96.3, 32.6], [-96.4, 32.6], [-96.4, 32.5]]]
+				]
+			}
+		}
+	]
+}`
+
+func TestParseGeofence(t *testing.T) {
+	g, err := ParseGeofence(strings.NewReader(dummyGeofenceJSON))
+	assert.Nil(t, err)
+	assert.Len(t, g.Boundaries, 3)
+
+	marketID, ok := g.MarketAt(austin)
+	assert.True(t, ok)
+	assert.Equal(t, "1", marketID)
+
+	_, ok = g.MarketAt(LatLng{Lat: 30.25, Lng: -97.75})
+	assert.False(t, ok, "Points in a hole shouldn't be in the market")
+
+	marketID, ok = g.MarketAt(LatLng{Lat: 32.55, Lng: -96.35})
+	assert.True(t, ok)
+	assert.Equal(t, "2", marketID)
+
+	assert.False(t, g.Contains(sydneyOpry))
+
+	_, err = ParseGeofence(strings.NewReader(`{"type": "Feature"}`))
+	assert.NotNil(t, err)
+}
+
+func TestParseGeofenceLongMarketIDs(t *testing.T) {
+	g, err := ParseGeofence(strings.NewReader(`{"type": "FeatureCollection", "features": [{
+		"type": "Feature",
+		"properties": {"market_id": 1000000},
+		"geometry": {"type": "Polygon", "coordinates": [[[-98.0, 30.0], [-97.5, 30.0], [-97.5, 30.5], [-98.0, 30.0]]]}
+	}]}`))
+	assert.Nil(t, err)
+
+	marketID, ok := g.MarketAt(LatLng{Lat: 30.1, Lng: -97.6})
+	assert.True(t, ok)
+	assert.Equal(t, "1000000", marketID)
+	assert.Nil(t, RequestFavor{Title: "Tacos", Wants: "tacos", Street: "123 Fake St", Zipcode: "78701", Lat: 30.1, Lng: -97.6, MarketID: 1000000}.Validate(&g))
+}
+
+func TestLoadGeofenceFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "favor")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "markets.geojson")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(dummyGeofenceJSON), 0644))
+
+	g, err := LoadGeofenceFile(path)
+	assert.Nil(t, err)
+	assert.True(t, g.Contains(austin))
+}
+
+func TestRequestFavorValidate(t *testing.T) {
+	g, err := ParseGeofence(strings.NewReader(dummyGeofenceJSON))
+	assert.Nil(t, err)
+
+	rf := RequestFavor{
+		Title:   "Torchy's Tacos",
+		Wants:   "1x Trailer Park",
+		Street:  "42 Wallaby Way",
+		Zipcode: "78701",
+		Lat:     austin.Lat,
+		Lng:     austin.Lng,
+	}
+	assert.Nil(t, rf.Validate(nil))
+	assert.Nil(t, rf.Validate(&g))
+
+	rf.MarketID = 2
+	assert.NotNil(t, rf.Validate(&g), "The wrong market should fail validation")
+
+	rf.MarketID = 0
+	rf.Lat, rf.Lng = sydneyOpry.Lat, sydneyOpry.Lng
+	assert.Nil(t, rf.Validate(nil))
+	assert.NotNil(t, rf.Validate(&g), "Undeliverable addresses should fail validation")
+
+	assert.NotNil(t, RequestFavor{Title: "Tacos"}.Validate(nil), "Missing fields should fail validation")
+
+	s, err := New(dummyToken)
+	if err != nil {
+		t.Errorf("Constructor failed with the following error: %v", err)
+		t.FailNow()
+	}
+	s.Geofence = &g
+	_, err = s.PlaceFavor(rf)
+	assert.NotNil(t, err, "PlaceFavor should refuse undeliverable addresses before making any requests")
+}
@@ -0,0 +1,273 @@
+package favor
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"text/tabwriter"
+)
+
+// GroupOrder collects items from several people into a single favor from
+// one Merchant. Participants add items until the organizer locks the order,
+// at which point it can be placed as one RequestFavor. Once the favor has
+// been delivered, the Receipt can be split up between everyone involved.
+type GroupOrder struct {
+	Organizer string
+	Merchant  Merchant
+	Favor     Favor
+
+	lock         sync.Mutex
+	locked       bool
+	placing      bool
+	participants []string
+	carts        map[string]*Cart
+}
+
+// ParticipantShare describes what one participant owes for a group order,
+// in cents. Food is their portion of the receipt's price, and the fees are
+// their proportional portion of the tip, delivery charge, and card fee.
+type ParticipantShare struct {
+	Participant    string
+	Subtotal       int64
+	Food           int64
+	Tip            int64
+	DeliveryCharge int64
+	CcFee          int64
+	Total          int64
+}
+
+// NewGroupOrder is a constructor function returning a new, unlocked GroupOrder.
+func NewGroupOrder(organizer string, m Merchant) *GroupOrder {
+	return &GroupOrder{
+		Organizer: organizer,
+		Merchant:  m,
+		carts:     map[string]*Cart{},
+	}
+}
+
+// AddItem adds an item to a participant's portion of the order.
+func (g *GroupOrder) AddItem(participant string, item CartItem) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.locked {
+		return fmt.Errorf("The group order has been locked, and can no longer be changed.")
+	}
+	if _, ok := g.carts[participant]; !ok {
+		g.participants = append(g.participants, participant)
+		g.carts[participant] = &Cart{}
+	}
+	g.carts[participant].Add(item)
+	return nil
+}
+
+// RemoveParticipant removes a participant and all of their items from the order.
+func (g *GroupOrder) RemoveParticipant(participant string) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.locked {
+		return fmt.Errorf("The group order has been locked, and can no longer be changed.")
+	}
+	delete(g.carts, participant)
+	for i, p := range g.participants {
+		if p == participant {
+			g.participants = append(g.participants[:i], g.participants[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Participants returns everyone who has added something to the order, in the
+// order they joined.
+func (g *GroupOrder) Participants() []string {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return append([]string{}, g.participants...)
+}
+
+// Lock prevents any further changes to the order. Only the organizer is
+// allowed to lock it.
+func (g *GroupOrder) Lock(by string) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if by != g.Organizer {
+		return fmt.Errorf("Only the organizer, %v, can lock the group order.", g.Organizer)
+	}
+	g.locked = true
+	return nil
+}
+
+// Unlock lets participants change the order again, as long as it hasn't been
+// placed. Only the organizer is allowed to unlock it.
+func (g *GroupOrder) Unlock(by string) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if by != g.Organizer {
+		return fmt.Errorf("Only the organizer, %v, can unlock the group order.", g.Organizer)
+	}
+	if g.placing || g.Favor.ID != "" {
+		return fmt.Errorf("The group order has already been placed, and can't be unlocked.")
+	}
+	g.locked = false
+	return nil
+}
+
+// IsLocked returns whether or not the order has been locked.
+func (g *GroupOrder) IsLocked() bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.locked
+}
+
+// Cart combines every participant's items into a single Cart. Each item is
+// labeled with who it's for, so the runner can keep everyone's food separate.
+func (g *GroupOrder) Cart() Cart {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	combined := Cart{}
+	for _, p := range g.participants {
+		for _, item := range g.carts[p].Items {
+			labeled := item
+			if labeled.Notes == "" {
+				labeled.Notes = fmt.Sprintf("for %v", p)
+			} else {
+				labeled.Notes = fmt.Sprintf("for %v, %v", p, labeled.Notes)
+			}
+			combined.Add(labeled)
+		}
+	}
+	return combined
+}
+
+// Place locks the order and places it with the Favor API as a single favor,
+// to be delivered to the provided address. Only the organizer is allowed to
+// place it, and only once. If placing it fails, the order goes back to being
+// as locked as it was before, so that it can be fixed and placed again.
+func (g *GroupOrder) Place(by string, c *Client, a Address) (Favor, error) {
+	g.lock.Lock()
+	switch {
+	case by != g.Organizer:
+		g.lock.Unlock()
+		return Favor{}, fmt.Errorf("Only the organizer, %v, can place the group order.", g.Organizer)
+	case g.Favor.ID != "":
+		g.lock.Unlock()
+		return Favor{}, fmt.Errorf("The group order has already been placed as favor %v.", g.Favor.ID)
+	case g.placing:
+		g.lock.Unlock()
+		return Favor{}, fmt.Errorf("The group order is already being placed.")
+	}
+	wasLocked := g.locked
+	g.locked, g.placing = true, true
+	g.lock.Unlock()
+
+	f, err := g.place(c, a)
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.placing = false
+	if err != nil {
+		g.locked = wasLocked
+		return Favor{}, err
+	}
+	g.Favor = f
+	return f, nil
+}
+
+func (g *GroupOrder) place(c *Client, a Address) (Favor, error) {
+	rf, err := g.Cart().RequestFavor(g.Merchant, a)
+	if err != nil {
+		return Favor{}, err
+	}
+	return c.PlaceFavor(rf)
+}
+
+// distribute splits amount up according to weights, handing out leftover
+// cents one at a time so that the pieces always add back up to amount.
+func distribute(amount int64, weights []int64) []int64 {
+	pieces := make([]int64, len(weights))
+	if len(weights) == 0 {
+		return pieces
+	}
+
+	var total int64
+	for _, w := range weights {
+		total += w
+	}
+
+	var assigned int64
+	for i, w := range weights {
+		if total == 0 {
+			pieces[i] = amount / int64(len(weights))
+		} else {
+			pieces[i] = amount * w / total
+		}
+		assigned += pieces[i]
+	}
+	for i := 0; assigned < amount; i = (i + 1) % len(pieces) {
+		pieces[i]++
+		assigned++
+	}
+	return pieces
+}
+
+// Split divides a receipt up between the participants, in proportion to the
+// estimated subtotal of each participant's items. If nobody's items have a
+// price, the receipt is split evenly.
+func (g *GroupOrder) Split(r Receipt) ([]ParticipantShare, error) {
+	amounts, err := r.Amounts()
+	if err != nil {
+		return nil, err
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if len(g.participants) == 0 {
+		return nil, fmt.Errorf("The group order has no participants to split the receipt between.")
+	}
+
+	weights := []int64{}
+	for _, p := range g.participants {
+		weights = append(weights, int64(g.carts[p].Subtotal()*100+0.5))
+	}
+
+	food := distribute(amounts.Price, weights)
+	tips := distribute(amounts.Tip, weights)
+	delivery := distribute(amounts.DeliveryCharge, weights)
+	ccFees := distribute(amounts.CcFeeAmount, weights)
+
+	shares := []ParticipantShare{}
+	for i, p := range g.participants {
+		share := ParticipantShare{
+			Participant:    p,
+			Subtotal:       weights[i],
+			Food:           food[i],
+			Tip:            tips[i],
+			DeliveryCharge: delivery[i],
+			CcFee:          ccFees[i],
+		}
+		share.Total = share.Food + share.Tip + share.DeliveryCharge + share.CcFee
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
+
+// SplitReport renders a per-person table of who owes what, for pasting into
+// chat or email after the food shows up.
+func SplitReport(shares []ParticipantShare) string {
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "PARTICIPANT\tFOOD\tTIP\tDELIVERY\tCC FEE\tTOTAL\t")
+
+	var total int64
+	for _, s := range shares {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t\n", s.Participant, FormatCents(s.Food), FormatCents(s.Tip), FormatCents(s.DeliveryCharge), FormatCents(s.CcFee), FormatCents(s.Total))
+		total += s.Total
+	}
+	fmt.Fprintf(w, "TOTAL\t\t\t\t\t%v\t\n", FormatCents(total))
+	w.Flush()
+	return b.String()
+}
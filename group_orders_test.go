@@ -0,0 +1,128 @@
+package favor
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestGroupOrder() *GroupOrder {
+	g := NewGroupOrder("greg", Merchant{ID: "1234", MarketID: "1", Name: "Farts McGregor's Corntopia"})
+	g.AddItem("greg", CartItem{Name: "Trailer Park", Price: 3})
+	g.AddItem("alice", CartItem{Name: "Queso", Price: 6})
+	g.AddItem("greg", CartItem{Name: "Trailer Park", Price: 3})
+	return g
+}
+
+func TestGroupOrderCart(t *testing.T) {
+	g := buildTestGroupOrder()
+
+	assert.Equal(t, []string{"greg", "alice"}, g.Participants())
+	assert.Equal(t, "2x Trailer Park - for greg\n1x Queso - for alice", g.Cart().Wants())
+
+	assert.NotNil(t, g.Lock("alice"), "Only the organizer should be able to lock the order")
+	assert.False(t, g.IsLocked())
+	assert.Nil(t, g.Lock("greg"))
+	err := g.AddItem("bob", CartItem{Name: "Chips"})
+	assert.NotNil(t, err, "Locked group orders shouldn't accept new items")
+	assert.Equal(t, []string{"greg", "alice"}, g.Participants())
+
+	assert.NotNil(t, g.Unlock("alice"))
+	assert.Nil(t, g.Unlock("greg"))
+	assert.Nil(t, g.AddItem("bob", CartItem{Name: "Chips"}))
+}
+
+func TestGroupOrderPlace(t *testing.T) {
+	s := setupMockClient(t, respondWith(`{"favor": {"id": "9876", "title": "Farts McGregor's Corntopia"}}`))
+
+	g := buildTestGroupOrder()
+	address := Address{Lat: "30.2", Lng: "-97.7", Street: "42 Wallaby Way", Zipcode: "2000"}
+	_, err := g.Place("alice", s, address)
+	assert.NotNil(t, err, "Only the organizer should be able to place the order")
+	assert.False(t, g.IsLocked())
+
+	f, err := g.Place("greg", s, address)
+	assert.Nil(t, err)
+	assert.Equal(t, "9876", f.ID)
+	assert.Equal(t, "9876", g.Favor.ID)
+	assert.True(t, g.IsLocked())
+
+	_, err = g.Place("greg", s, address)
+	assert.NotNil(t, err, "A group order shouldn't be placed twice")
+	assert.NotNil(t, g.Unlock("greg"), "A placed group order shouldn't be unlockable")
+}
+
+func TestGroupOrderPlaceFailure(t *testing.T) {
+	s := setupMockClient(t, respondWith(`this is not json`))
+
+	address := Address{Lat: "30.2", Lng: "-97.7", Street: "42 Wallaby Way", Zipcode: "2000"}
+	g := buildTestGroupOrder()
+	_, err := g.Place("greg", s, address)
+	assert.NotNil(t, err)
+	assert.False(t, g.IsLocked(), "A group order that failed to place should be unlocked again")
+	assert.Nil(t, g.AddItem("bob", CartItem{Name: "Chips"}))
+
+	assert.Nil(t, g.Lock("greg"))
+	_, err = g.Place("greg", s, address)
+	assert.NotNil(t, err)
+	assert.True(t, g.IsLocked(), "A group order the organizer locked should stay locked")
+
+	empty := NewGroupOrder("greg", Merchant{ID: "1234"})
+	_, err = empty.Place("greg", s, address)
+	assert.NotNil(t, err)
+	assert.False(t, empty.IsLocked())
+}
+
+func TestGroupOrderPlaceInProgress(t *testing.T) {
+	arrived, release := make(chan bool), make(chan bool)
+	s := setupMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- true
+		<-release
+		fmt.Fprintln(w, `{"favor": {"id": "9876"}}`)
+	}))
+
+	address := Address{Lat: "30.2", Lng: "-97.7", Street: "42 Wallaby Way", Zipcode: "2000"}
+	g := buildTestGroupOrder()
+	done := make(chan error)
+	go func() {
+		_, err := g.Place("greg", s, address)
+		done <- err
+	}()
+	<-arrived
+
+	_, err := g.Place("greg", s, address)
+	assert.NotNil(t, err, "A group order shouldn't be placed while it's already being placed")
+	assert.NotNil(t, g.Unlock("greg"))
+
+	release <- true
+	assert.Nil(t, <-done)
+	assert.Equal(t, "9876", g.Favor.ID)
+}
+
+func TestGroupOrderSplit(t *testing.T) {
+	g := buildTestGroupOrder()
+	r := Receipt{
+		Price:          "12.00",
+		Tip:            "3.01",
+		DeliveryCharge: "5.00",
+		CcFeeAmount:    "0.50",
+	}
+
+	shares, err := g.Split(r)
+	assert.Nil(t, err)
+	expected := []ParticipantShare{
+		{Participant: "greg", Subtotal: 600, Food: 600, Tip: 151, DeliveryCharge: 250, CcFee: 25, Total: 1026},
+		{Participant: "alice", Subtotal: 600, Food: 600, Tip: 150, DeliveryCharge: 250, CcFee: 25, Total: 1025},
+	}
+	assert.Equal(t, expected, shares)
+
+	report := SplitReport(shares)
+	assert.True(t, strings.Contains(report, "10.26"))
+	assert.True(t, strings.Contains(report, "20.51"))
+
+	_, err = g.Split(Receipt{Price: "lol"})
+	assert.NotNil(t, err)
+}
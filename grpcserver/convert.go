@@ -0,0 +1,167 @@
+package grpcserver
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/favorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// locationFrom converts the API's pair of strings into a Location, or nil if
+// they don't make a valid point.
+func locationFrom(lat, lng string) *favorpb.Location {
+	p, err := favor.ParseLatLng(lat, lng)
+	if err != nil {
+		return nil
+	}
+	return &favorpb.Location{Lat: p.Lat, Lng: p.Lng}
+}
+
+func userFrom(u favor.User) *favorpb.User {
+	if u == (favor.User{}) {
+		return nil
+	}
+	return &favorpb.User{Id: u.ID, Forename: u.Forename, Surname: u.Surname, Phone: u.Phone, Email: u.Email}
+}
+
+func addressFrom(a favor.Address) *favorpb.Address {
+	if a == (favor.Address{}) {
+		return nil
+	}
+	return &favorpb.Address{
+		Id:        a.ID,
+		Street:    a.Street,
+		Zipcode:   a.Zipcode,
+		Apartment: a.Apartment,
+		Notes:     a.Notes,
+		Location:  locationFrom(a.Lat, a.Lng),
+	}
+}
+
+func addressTo(a *favorpb.Address) favor.Address {
+	fa := favor.Address{
+		ID:        a.GetId(),
+		Street:    a.GetStreet(),
+		Zipcode:   a.GetZipcode(),
+		Apartment: a.GetApartment(),
+		Notes:     a.GetNotes(),
+	}
+	if l := a.GetLocation(); l != nil {
+		fa.Lat = strconv.FormatFloat(l.Lat, 'f', -1, 64)
+		fa.Lng = strconv.FormatFloat(l.Lng, 'f', -1, 64)
+	}
+	return fa
+}
+
+func receiptFrom(r favor.Receipt) (*favorpb.Receipt, error) {
+	if r == (favor.Receipt{}) {
+		return nil, nil
+	}
+	ra, err := r.Amounts()
+	if err != nil {
+		return nil, err
+	}
+	return &favorpb.Receipt{
+		PaidCents:           ra.Paid,
+		PriceCents:          ra.Price,
+		TipCents:            ra.Tip,
+		DeliveryChargeCents: ra.DeliveryCharge,
+		CardFeeCents:        ra.CcFeeAmount,
+		TotalCents:          ra.Total(),
+	}, nil
+}
+
+func favorFrom(f favor.Favor) (*favorpb.Favor, error) {
+	receipt, err := receiptFrom(f.Receipt)
+	if err != nil {
+		return nil, err
+	}
+	out := &favorpb.Favor{
+		Id:              f.ID,
+		Title:           f.Title,
+		Items:           f.Items,
+		Stage:           f.Stage,
+		Status:          f.LastStatus,
+		Finished:        f.IsFinished(),
+		MerchantId:      f.MerchantID,
+		Customer:        userFrom(f.Customer),
+		Runner:          userFrom(f.Runner),
+		DeliveryAddress: addressFrom(f.DeliveryAddress),
+		Receipt:         receipt,
+	}
+	if out.MerchantId == "" {
+		out.MerchantId = f.Merchant.ID
+	}
+	if f.CreatedAt > 0 {
+		out.CreatedAt = timestamppb.New(time.Unix(int64(f.CreatedAt), 0))
+	}
+	return out, nil
+}
+
+// requestFavorTo converts a RequestFavor into what the Favor API expects, and
+// checks it for anything the API would be unhappy about.
+func requestFavorTo(rf *favorpb.RequestFavor) (favor.RequestFavor, error) {
+	out := favor.RequestFavor{
+		Title:   rf.GetTitle(),
+		Wants:   rf.GetWants(),
+		Street:  rf.GetStreet(),
+		Zipcode: rf.GetZipcode(),
+		Apt:     rf.GetApartment(),
+		Notes:   rf.GetNotes(),
+	}
+	if l := rf.GetLocation(); l != nil {
+		out.Lat, out.Lng = l.Lat, l.Lng
+	}
+
+	var err error
+	if id := rf.GetMerchantId(); id != "" {
+		if out.MerchantID, err = strconv.Atoi(id); err != nil {
+			return favor.RequestFavor{}, fmt.Errorf("merchant_id %q is not a valid ID", id)
+		}
+	}
+	if id := rf.GetMarketId(); id != "" {
+		if out.MarketID, err = strconv.Atoi(id); err != nil {
+			return favor.RequestFavor{}, fmt.Errorf("market_id %q is not a valid ID", id)
+		}
+	}
+	if err := out.Validate(nil); err != nil {
+		return favor.RequestFavor{}, err
+	}
+	return out, nil
+}
+
+// merchantFrom converts a merchant. near is where the caller is, if they said,
+// and is used to work out how far away the merchant is.
+func merchantFrom(m favor.Merchant, now time.Time, near *favor.LatLng) *favorpb.Merchant {
+	out := &favorpb.Merchant{
+		Id:           m.ID,
+		Name:         m.Name,
+		Phone:        m.Phone,
+		Address:      m.Address,
+		City:         m.City,
+		State:        m.State,
+		Zipcode:      m.Zipcode,
+		Cuisine:      m.Cuisine,
+		MarketId:     m.MarketID,
+		FranchiseId:  m.FranchiseID,
+		Location:     locationFrom(m.Lat, m.Lng),
+		ExpandedMenu: m.HasExpandedMenu == "1",
+		CarOnly:      m.IsCarOnly == "1",
+	}
+	if len(m.Hours) > 0 {
+		open := m.IsOpenAt(now)
+		out.OpenNow = &open
+	}
+	for _, h := range m.Hours {
+		out.Hours = append(out.Hours, h.String())
+	}
+	if near != nil {
+		if d, err := m.DistanceFrom(*near); err == nil {
+			out.DistanceMeters = &d
+		}
+	}
+	return out
+}
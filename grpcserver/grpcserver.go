@@ -0,0 +1,348 @@
+// Package grpcserver implements the gRPC service defined in favorpb, by
+// delegating to a Client for each caller. Callers authenticate with the same
+// API keys as the REST gateway, sent as "authorization: Bearer <key>" or
+// "x-api-key: <key>" metadata.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/favorpb"
+	"github.com/verygoodsoftwarenotvirus/favor/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMinWatchInterval is how often WatchFavor is willing to poll, unless
+// told otherwise. Callers asking for anything more often get this instead, so
+// that one impatient service can't hammer the Favor API.
+const DefaultMinWatchInterval = 5 * time.Second
+
+// Server implements favorpb.FavorServiceServer. Keys is the only thing that
+// has to be set.
+type Server struct {
+	favorpb.UnimplementedFavorServiceServer
+
+	Keys server.KeyStore
+
+	// NewClient builds the client a caller's requests are made with. It
+	// defaults to favor.NewWithTokenSource, and is mostly here for tests.
+	NewClient func(tokens favor.TokenSource) (*favor.Client, error)
+
+	// MinWatchInterval defaults to DefaultMinWatchInterval.
+	MinWatchInterval time.Duration
+
+	// Now is used to figure out which merchants are open.
+	Now func() time.Time
+}
+
+// GRPC returns a gRPC server with the service registered on it, and the
+// interceptors that handle authentication installed. Any other options are
+// passed along to grpc.NewServer.
+func (s *Server) GRPC(opts ...grpc.ServerOption) *grpc.Server {
+	if s.NewClient == nil {
+		s.NewClient = favor.NewWithTokenSource
+	}
+	if s.MinWatchInterval <= 0 {
+		s.MinWatchInterval = DefaultMinWatchInterval
+	}
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(s.authenticateUnary),
+		grpc.ChainStreamInterceptor(s.authenticateStream),
+	)
+	g := grpc.NewServer(opts...)
+	favorpb.RegisterFavorServiceServer(g, s)
+	return g
+}
+
+type clientKey struct{}
+
+// authenticate figures out who's calling, and returns a context carrying the
+// client their requests should be made with.
+func (s *Server) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	key := ""
+	if values := md.Get("authorization"); len(values) > 0 && strings.HasPrefix(values[0], "Bearer ") {
+		key = strings.TrimSpace(strings.TrimPrefix(values[0], "Bearer "))
+	} else if values := md.Get("x-api-key"); len(values) > 0 {
+		key = values[0]
+	}
+
+	caller, ok := s.Keys.Lookup(key)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "a valid API key is required")
+	}
+	client, err := s.NewClient(caller.Tokens)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "building a client for %v failed: %v", caller.Name, err)
+	}
+	return context.WithValue(ctx, clientKey{}, client), nil
+}
+
+func (s *Server) authenticateUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// authenticatedStream is a stream with the caller's client in its context.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (as authenticatedStream) Context() context.Context {
+	return as.ctx
+}
+
+func (s *Server) authenticateStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, authenticatedStream{ss, ctx})
+}
+
+// client returns the caller's client, which the interceptors put there.
+func client(ctx context.Context) *favor.Client {
+	return ctx.Value(clientKey{}).(*favor.Client)
+}
+
+// upstream wraps errors from the Favor API, passing along the ones that mean
+// something to the caller, like a favor no market delivers to.
+func upstream(err error) error {
+	nm := &favor.NoMarketError{}
+	if errors.As(err, &nm) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	se := &favor.StatusError{}
+	if errors.As(err, &se) {
+		switch se.StatusCode {
+		case http.StatusNotFound:
+			return status.Error(codes.NotFound, err.Error())
+		case http.StatusUnauthorized:
+			// Unauthenticated would tell the caller their API key is wrong,
+			// when it's the Favor token behind it that was turned down.
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+// ListFavors lists the caller's favors.
+func (s *Server) ListFavors(ctx context.Context, req *favorpb.ListFavorsRequest) (*favorpb.ListFavorsResponse, error) {
+	favors, err := client(ctx).GetFavors()
+	if err != nil {
+		return nil, upstream(err)
+	}
+	out := &favorpb.ListFavorsResponse{}
+	for _, f := range favors {
+		converted, err := favorFrom(f)
+		if err != nil {
+			return nil, upstream(err)
+		}
+		out.Favors = append(out.Favors, converted)
+	}
+	return out, nil
+}
+
+// GetFavor gets a single favor.
+func (s *Server) GetFavor(ctx context.Context, req *favorpb.GetFavorRequest) (*favorpb.Favor, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	f, err := client(ctx).GetFavor(req.GetId())
+	if err != nil {
+		return nil, upstream(err)
+	}
+	if f.ID == "" {
+		return nil, status.Errorf(codes.NotFound, "there's no favor %v", req.GetId())
+	}
+	out, err := favorFrom(f)
+	if err != nil {
+		return nil, upstream(err)
+	}
+	return out, nil
+}
+
+// PlaceFavor places a favor.
+func (s *Server) PlaceFavor(ctx context.Context, req *favorpb.PlaceFavorRequest) (*favorpb.Favor, error) {
+	rf, err := requestFavorTo(req.GetFavor())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	f, err := client(ctx).PlaceFavor(rf)
+	if err != nil {
+		return nil, upstream(err)
+	}
+	out, err := favorFrom(f)
+	if err != nil {
+		return nil, upstream(err)
+	}
+	return out, nil
+}
+
+// WatchFavor streams a favor as it changes, by way of Client.WatchFavor.
+func (s *Server) WatchFavor(req *favorpb.WatchFavorRequest, stream grpc.ServerStreamingServer[favorpb.FavorUpdate]) error {
+	if req.GetId() == "" {
+		return status.Error(codes.InvalidArgument, "id is required")
+	}
+	interval := favor.DefaultWatchInterval
+	if req.GetInterval() != nil {
+		interval = req.GetInterval().AsDuration()
+	}
+	if interval < s.MinWatchInterval {
+		interval = s.MinWatchInterval
+	}
+
+	ctx := stream.Context()
+	first := true
+	err := client(ctx).WatchFavor(ctx, req.GetId(), interval, func(f favor.Favor, changes favor.FavorChanges) error {
+		if first {
+			if f.ID == "" {
+				return status.Errorf(codes.NotFound, "there's no favor %v", req.GetId())
+			}
+			changes = favor.FavorChanges{Stage: true, LastStatus: true, Runner: true, Receipt: true}
+			first = false
+		}
+		converted, err := favorFrom(f)
+		if err != nil {
+			return upstream(err)
+		}
+		return stream.Send(&favorpb.FavorUpdate{
+			Favor:          converted,
+			StageChanged:   changes.Stage,
+			StatusChanged:  changes.LastStatus,
+			RunnerChanged:  changes.Runner,
+			ReceiptChanged: changes.Receipt,
+		})
+	})
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch err {
+	case context.Canceled:
+		return status.Error(codes.Canceled, err.Error())
+	case context.DeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return upstream(err)
+}
+
+// ListMerchants lists the merchants near a point, closest first, or best
+// match first when there's a query.
+func (s *Server) ListMerchants(ctx context.Context, req *favorpb.ListMerchantsRequest) (*favorpb.ListMerchantsResponse, error) {
+	if req.GetNear() == nil {
+		return nil, status.Error(codes.InvalidArgument, "near is required")
+	}
+	near := favor.LatLng{Lat: req.GetNear().Lat, Lng: req.GetNear().Lng}
+	if err := near.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	now := s.Now()
+
+	var merchants favor.Merchants
+	if q := strings.TrimSpace(req.GetQuery()); q != "" {
+		results, err := client(ctx).SearchMerchants(ctx, q, near)
+		if err != nil {
+			return nil, upstream(err)
+		}
+		for _, r := range results {
+			merchants = append(merchants, r.Merchant)
+		}
+	} else {
+		found, err := client(ctx).GetMerchants(near.Lat, near.Lng)
+		if err != nil {
+			return nil, upstream(err)
+		}
+		merchants = favor.Merchants(found).SortByDistance(near)
+	}
+	if req.GetOpenOnly() {
+		merchants = merchants.Filter(favor.OpenAt(now))
+	}
+
+	out := &favorpb.ListMerchantsResponse{}
+	for _, m := range merchants {
+		out.Merchants = append(out.Merchants, merchantFrom(m, now, &near))
+	}
+	return out, nil
+}
+
+// GetMerchant gets a single merchant.
+func (s *Server) GetMerchant(ctx context.Context, req *favorpb.GetMerchantRequest) (*favorpb.Merchant, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	m, err := client(ctx).GetMerchant(req.GetId())
+	if err != nil {
+		return nil, upstream(err)
+	}
+	if m.ID == "" {
+		return nil, status.Errorf(codes.NotFound, "there's no merchant %v", req.GetId())
+	}
+	return merchantFrom(m, s.Now(), nil), nil
+}
+
+// GetMe gets the profile of the user behind the caller's token.
+func (s *Server) GetMe(ctx context.Context, req *favorpb.GetMeRequest) (*favorpb.User, error) {
+	u, err := client(ctx).GetMe()
+	if err != nil {
+		return nil, upstream(err)
+	}
+	if out := userFrom(u); out != nil {
+		return out, nil
+	}
+	return &favorpb.User{}, nil
+}
+
+// ListAddresses lists the caller's saved addresses.
+func (s *Server) ListAddresses(ctx context.Context, req *favorpb.ListAddressesRequest) (*favorpb.ListAddressesResponse, error) {
+	addresses, err := client(ctx).ListAddresses()
+	if err != nil {
+		return nil, upstream(err)
+	}
+	out := &favorpb.ListAddressesResponse{}
+	for _, a := range addresses {
+		out.Addresses = append(out.Addresses, addressFrom(a))
+	}
+	return out, nil
+}
+
+// CreateAddress saves an address.
+func (s *Server) CreateAddress(ctx context.Context, req *favorpb.CreateAddressRequest) (*favorpb.Address, error) {
+	a := req.GetAddress()
+	missing := []string{}
+	if strings.TrimSpace(a.GetStreet()) == "" {
+		missing = append(missing, "street")
+	}
+	if strings.TrimSpace(a.GetZipcode()) == "" {
+		missing = append(missing, "zipcode")
+	}
+	if a.GetLocation() == nil {
+		missing = append(missing, "location")
+	}
+	if len(missing) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "the address is missing required fields: %v", strings.Join(missing, ", "))
+	}
+
+	fa := addressTo(a)
+	fa.ID = ""
+	created, err := client(ctx).CreateAddress(fa)
+	if err != nil {
+		return nil, upstream(err)
+	}
+	return addressFrom(created), nil
+}
@@ -0,0 +1,220 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/favorpb"
+	"github.com/verygoodsoftwarenotvirus/favor/favortest"
+	"github.com/verygoodsoftwarenotvirus/favor/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var dummyToken = "thisisarandomstringfortestinglol"
+
+const dummyKey = "letmein"
+
+// buildTestService starts the service in memory in front of a pretend Favor
+// API, and returns a client for it.
+func buildTestService(t *testing.T, handler http.HandlerFunc) (favorpb.FavorServiceClient, func()) {
+	upstream := favortest.NewClient(t, handler)
+	s := &Server{
+		Keys: server.Keys{dummyKey: server.Caller{Name: "billing", Tokens: favor.StaticToken(dummyToken)}},
+		NewClient: func(tokens favor.TokenSource) (*favor.Client, error) {
+			client := *upstream
+			client.TokenSource = tokens
+			return &client, nil
+		},
+		MinWatchInterval: time.Millisecond,
+		Now:              func() time.Time { return time.Date(2016, time.March, 9, 12, 0, 0, 0, time.UTC) },
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	g := s.GRPC()
+	go g.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Errorf("Dialing the test service failed with the following error: %v", err)
+		t.FailNow()
+	}
+	return favorpb.NewFavorServiceClient(conn), func() {
+		conn.Close()
+		g.Stop()
+	}
+}
+
+func authenticated() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+dummyKey)
+}
+
+func TestAuthentication(t *testing.T) {
+	c, done := buildTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"user": {"id": "1234", "forename": "Greg"}}`)
+	})
+	defer done()
+
+	_, err := c.GetMe(context.Background(), &favorpb.GetMeRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "letmeinplease")
+	_, err = c.GetMe(ctx, &favorpb.GetMeRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "x-api-key", dummyKey)
+	u, err := c.GetMe(ctx, &favorpb.GetMeRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Greg", u.GetForename())
+
+	stream, err := c.WatchFavor(context.Background(), &favorpb.WatchFavorRequest{Id: "9876"})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "Streams should need a key too")
+}
+
+func TestFavors(t *testing.T) {
+	var placed url.Values
+	c, done := buildTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v5/favors/":
+			fmt.Fprintln(w, `{"favors": [{"id": "9876", "title": "Torchy's Tacos", "stage": "delivered", "created_at": 1457524800, "receipt": {"price": "10.00", "tip": "2.00", "delivery_charge": "1.50", "cc_fee_amount": "0.35"}}]}`)
+		case "GET /api/v5/favors/404":
+			// the Favor API doesn't 404 for favors it can't find
+			fmt.Fprintln(w, `{"favor": null}`)
+		case "GET /api/v5/merchant/401":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "GET /api/v5/merchant/500":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "GET /api/v5/markets":
+			fmt.Fprintln(w, `{"markets": [{"id": "7", "name": "Austin", "lat": "30.2672", "lng": "-97.7431", "radius": 40000}]}`)
+		case "POST /api/v5/favors/":
+			r.ParseForm()
+			placed = r.PostForm
+			fmt.Fprintln(w, `{"favor": {"id": "9877", "title": "Torchy's Tacos", "stage": "pending"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer done()
+
+	list, err := c.ListFavors(authenticated(), &favorpb.ListFavorsRequest{})
+	assert.Nil(t, err)
+	if assert.Len(t, list.GetFavors(), 1) {
+		f := list.GetFavors()[0]
+		assert.True(t, f.GetFinished())
+		assert.Equal(t, int64(1385), f.GetReceipt().GetTotalCents())
+		assert.Equal(t, int64(1457524800), f.GetCreatedAt().GetSeconds())
+		assert.Nil(t, f.GetRunner(), "Favors without a runner shouldn't have an empty one")
+	}
+
+	_, err = c.GetFavor(authenticated(), &favorpb.GetFavorRequest{Id: "404"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = c.GetFavor(authenticated(), &favorpb.GetFavorRequest{Id: "gone"})
+	assert.Equal(t, codes.NotFound, status.Code(err), "Actual 404s should be passed along too")
+
+	stream, err := c.WatchFavor(authenticated(), &favorpb.WatchFavorRequest{Id: "404", Interval: durationpb.New(time.Nanosecond)})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = c.GetMerchant(authenticated(), &favorpb.GetMerchantRequest{Id: "401"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "Favor turning down the token isn't the caller's API key being wrong")
+
+	_, err = c.GetMerchant(authenticated(), &favorpb.GetMerchantRequest{Id: "500"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	_, err = c.PlaceFavor(authenticated(), &favorpb.PlaceFavorRequest{Favor: &favorpb.RequestFavor{Title: "Torchy's Tacos"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	f, err := c.PlaceFavor(authenticated(), &favorpb.PlaceFavorRequest{Favor: &favorpb.RequestFavor{
+		Title:      "Torchy's Tacos",
+		Wants:      "2x Trailer Park",
+		Location:   &favorpb.Location{Lat: 30.2672, Lng: -97.7431},
+		Street:     "123 Fake St",
+		Zipcode:    "78701",
+		MerchantId: "2",
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, "9877", f.GetId())
+	assert.Equal(t, "2", placed.Get("merchant_id"))
+	assert.Equal(t, "7", placed.Get("market_id"))
+}
+
+func TestWatchFavor(t *testing.T) {
+	stages := []string{"pending", "assigned", "assigned", "delivered"}
+	var polls int32
+	c, done := buildTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(&polls, 1)) - 1
+		if i >= len(stages) {
+			i = len(stages) - 1
+		}
+		fmt.Fprintf(w, `{"favor": {"id": "9876", "stage": %q}}`, stages[i])
+	})
+	defer done()
+
+	stream, err := c.WatchFavor(authenticated(), &favorpb.WatchFavorRequest{Id: "9876", Interval: durationpb.New(time.Nanosecond)})
+	assert.Nil(t, err)
+
+	seen := []string{}
+	for {
+		update, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if !assert.Nil(t, err) {
+			break
+		}
+		if len(seen) == 0 {
+			assert.True(t, update.GetStageChanged() && update.GetRunnerChanged(), "Everything about the first update should count as changed")
+		} else {
+			assert.True(t, update.GetStageChanged())
+			assert.False(t, update.GetRunnerChanged())
+		}
+		seen = append(seen, update.GetFavor().GetStage())
+	}
+	assert.Equal(t, []string{"pending", "assigned", "delivered"}, seen)
+}
+
+func TestListMerchants(t *testing.T) {
+	c, done := buildTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"merchants": [
+			{"id": "1", "name": "Far Away Tacos", "lat": "30.4", "lng": "-97.7"},
+			{"id": "2", "name": "Torchy's Tacos", "lat": "30.2672", "lng": "-97.7431", "is_car_only": "1"}
+		]}`)
+	})
+	defer done()
+
+	_, err := c.ListMerchants(authenticated(), &favorpb.ListMerchantsRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	list, err := c.ListMerchants(authenticated(), &favorpb.ListMerchantsRequest{Near: &favorpb.Location{Lat: 30.2672, Lng: -97.7431}})
+	assert.Nil(t, err)
+	if assert.Len(t, list.GetMerchants(), 2) {
+		m := list.GetMerchants()[0]
+		assert.Equal(t, "Torchy's Tacos", m.GetName())
+		assert.True(t, m.GetCarOnly())
+		assert.NotNil(t, m.DistanceMeters)
+		assert.Nil(t, m.OpenNow, "Merchants without hours shouldn't claim to be open or closed")
+	}
+}
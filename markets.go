@@ -0,0 +1,157 @@
+package favor
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Market represents a city or region Favor operates in. Every Merchant and
+// every favor belongs to one. Radius is how far from the center of the market,
+// in meters, Favor is willing to deliver. I haven't seen it come back as
+// anything but a round number, so it's probably an approximation on their end too.
+type Market struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	City     string  `json:"city,omitempty"`
+	State    string  `json:"state,omitempty"`
+	Timezone string  `json:"timezone,omitempty"`
+	Lat      string  `json:"lat"`
+	Lng      string  `json:"lng"`
+	Radius   float64 `json:"radius,omitempty"`
+}
+
+// Markets is a collection of markets.
+type Markets []Market
+
+// Contains returns whether the point is within the market's delivery radius.
+func (m Market) Contains(p LatLng) bool {
+	center, err := ParseLatLng(m.Lat, m.Lng)
+	if err != nil || m.Radius <= 0 {
+		return false
+	}
+	return center.DistanceTo(p) <= m.Radius
+}
+
+// For returns the market that a point belongs to. If the point is within more
+// than one market, the one whose center is closest wins.
+func (ms Markets) For(p LatLng) (Market, bool) {
+	var best Market
+	bestDistance := -1.0
+	for _, m := range ms {
+		if !m.Contains(p) {
+			continue
+		}
+		center, _ := ParseLatLng(m.Lat, m.Lng)
+		if d := center.DistanceTo(p); bestDistance < 0 || d < bestDistance {
+			best, bestDistance = m, d
+		}
+	}
+	return best, bestDistance >= 0
+}
+
+// GetMarkets is used to retrieve every market Favor operates in.
+func (c Client) GetMarkets() (Markets, error) {
+	uri := c.BuildURL("markets", map[string]string{})
+	marketData, err := c.makeAPIRequest("get", uri)
+	if err != nil {
+		return nil, err
+	}
+	mr := struct {
+		Markets Markets `json:"markets"`
+	}{}
+
+	err = json.Unmarshal(marketData, &mr)
+	if err != nil {
+		return nil, err
+	}
+	return mr.Markets, nil
+}
+
+// MarketFor is used to figure out which market a point belongs to.
+func (c Client) MarketFor(lat, lng float64) (Market, error) {
+	p := LatLng{Lat: lat, Lng: lng}
+	if err := p.Validate(); err != nil {
+		return Market{}, err
+	}
+	markets, err := c.GetMarkets()
+	if err != nil {
+		return Market{}, err
+	}
+	m, ok := markets.For(p)
+	if !ok {
+		return Market{}, fmt.Errorf("No market serves %v.", p)
+	}
+	return m, nil
+}
+
+// NoMarketError is returned when there's no market that delivers to a place,
+// so that callers can tell it apart from the Favor API having a bad day.
+type NoMarketError struct {
+	Location LatLng
+}
+
+func (e *NoMarketError) Error() string {
+	return fmt.Sprintf("No market serves %v.", e.Location)
+}
+
+// WithMarket fills in the request's MarketID from its delivery coordinates,
+// using markets from GetMarkets. They hardly ever change, so it's fine to hang
+// onto them between requests. Requests that already have a MarketID are left alone.
+func (rf RequestFavor) WithMarket(markets Markets) (RequestFavor, error) {
+	if rf.MarketID != 0 {
+		return rf, nil
+	}
+	p := rf.Location()
+	if err := p.Validate(); err != nil {
+		return rf, err
+	}
+	m, ok := markets.For(p)
+	if !ok {
+		return rf, &NoMarketError{Location: p}
+	}
+	id, err := strconv.Atoi(m.ID)
+	if err != nil {
+		return rf, fmt.Errorf("Market %v has an ID that isn't a number: %q", m.Name, m.ID)
+	}
+	rf.MarketID = id
+	return rf, nil
+}
+
+// Franchise is a group of merchants that share an owner, like every location
+// of a chain restaurant.
+type Franchise struct {
+	ID        string
+	Name      string
+	Merchants Merchants
+}
+
+// Franchises groups the merchants by franchise. Merchants that don't belong to
+// a franchise are left out. Franchises are named after their first merchant,
+// and sorted by name.
+func (m Merchants) Franchises() []Franchise {
+	byID := map[string]*Franchise{}
+	order := []string{}
+	for _, merchant := range m {
+		if merchant.FranchiseID == "" {
+			continue
+		}
+		f, ok := byID[merchant.FranchiseID]
+		if !ok {
+			f = &Franchise{ID: merchant.FranchiseID, Name: merchant.Name}
+			byID[merchant.FranchiseID] = f
+			order = append(order, merchant.FranchiseID)
+		}
+		f.Merchants = append(f.Merchants, merchant)
+	}
+
+	franchises := []Franchise{}
+	for _, id := range order {
+		franchises = append(franchises, *byID[id])
+	}
+	sort.SliceStable(franchises, func(i, j int) bool {
+		return franchises[i].Name < franchises[j].Name
+	})
+	return franchises
+}
@@ -0,0 +1,95 @@
+package favor
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var dummyMarketsResponse = `
+{
+	"markets": [
+		{"id": "1", "name": "Austin", "lat": "30.2672", "lng": "-97.7431", "radius": 40000},
+		{"id": "2", "name": "Dallas", "lat": "32.7767", "lng": "-96.7970", "radius": 40000}
+	]
+}`
+
+func TestMarketFor(t *testing.T) {
+	s := setupMockClient(t, respondWith(dummyMarketsResponse))
+
+	markets, err := s.GetMarkets()
+	assert.Nil(t, err)
+	assert.Len(t, markets, 2)
+
+	m, err := s.MarketFor(30.3, -97.7)
+	assert.Nil(t, err)
+	assert.Equal(t, "Austin", m.Name)
+
+	_, err = s.MarketFor(sydneyOpry.Lat, sydneyOpry.Lng)
+	assert.NotNil(t, err, "Sydney shouldn't be in any market")
+}
+
+func TestMerchantsFranchises(t *testing.T) {
+	franchises := filterableMerchants.Franchises()
+	if assert.Len(t, franchises, 1) {
+		assert.Equal(t, "10", franchises[0].ID)
+		assert.Equal(t, "Torchy's Tacos", franchises[0].Name)
+		assert.Len(t, franchises[0].Merchants, 2)
+	}
+}
+
+func TestRequestFavorWithMarket(t *testing.T) {
+	markets := Markets{
+		{ID: "1", Name: "Austin", Lat: "30.2672", Lng: "-97.7431", Radius: 40000},
+		{ID: "2", Name: "Dallas", Lat: "32.7767", Lng: "-96.7970", Radius: 40000},
+	}
+
+	rf, err := RequestFavor{Title: "Tacos", Lat: 32.78, Lng: -96.8}.WithMarket(markets)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, rf.MarketID)
+
+	rf, err = RequestFavor{Title: "Tacos", Lat: 32.78, Lng: -96.8, MarketID: 5}.WithMarket(markets)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, rf.MarketID, "a MarketID that's already set should be left alone")
+
+	_, err = RequestFavor{Title: "Tacos", Lat: 40.71, Lng: -74.0}.WithMarket(markets)
+	assert.NotNil(t, err, "places no market serves should be an error")
+
+	_, err = RequestFavor{Title: "Tacos", Lat: 91}.WithMarket(markets)
+	assert.NotNil(t, err, "bad coordinates should be an error")
+}
+
+func TestPlaceFavorLooksUpMarket(t *testing.T) {
+	var paths []string
+	var postedMarketID string
+
+	s := setupMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/api/v5/markets":
+			fmt.Fprintln(w, dummyMarketsResponse)
+		case "/api/v5/favors/":
+			r.ParseForm()
+			postedMarketID = r.PostForm.Get("market_id")
+			fmt.Fprintln(w, `{"favor": {"id": "9876"}}`)
+		}
+	}))
+
+	f, err := s.PlaceFavor(RequestFavor{Title: "Tacos", Lat: 32.78, Lng: -96.8})
+	assert.Nil(t, err)
+	assert.Equal(t, "9876", f.ID)
+	assert.Equal(t, "2", postedMarketID)
+
+	paths = nil
+	_, err = s.PlaceFavor(RequestFavor{Title: "Tacos", Lat: 32.78, Lng: -96.8, MarketID: 5})
+	assert.Nil(t, err)
+	assert.Equal(t, "5", postedMarketID)
+	assert.Equal(t, []string{"/api/v5/favors/"}, paths, "a MarketID that's already set shouldn't be looked up")
+
+	paths = nil
+	_, err = s.PlaceFavor(RequestFavor{Title: "Pizza", Lat: 40.71, Lng: -74.0})
+	assert.IsType(t, &NoMarketError{}, err)
+	assert.Equal(t, []string{"/api/v5/markets"}, paths, "favors no market serves shouldn't be placed")
+}
@@ -0,0 +1,130 @@
+package favor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MenuOption represents a modifier that can be applied to a Meal, like
+// extra cheese or a different size. Price is the amount the option adds
+// to the price of the meal, and like most numbers in the Favor API, it
+// comes back as a string.
+type MenuOption struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Price string `json:"price"`
+}
+
+// Meal represents a single orderable item on a Merchant's menu.
+type Meal struct {
+	ID          string       `json:"id"`
+	CategoryID  string       `json:"category_id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Price       string       `json:"price"`
+	Options     []MenuOption `json:"options,omitempty"`
+}
+
+// MenuCategory is a grouping of meals on a menu, like "Appetizers" or "Tacos"
+type MenuCategory struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Meals []Meal `json:"meals"`
+}
+
+// Menu represents the expanded menu for a Merchant. As far as I can tell,
+// only merchants whose HasExpandedMenu field is "1" will return anything
+// useful here.
+type Menu struct {
+	MerchantID string         `json:"merchant_id"`
+	Categories []MenuCategory `json:"categories"`
+}
+
+// FindMeal searches the menu for a meal with the given ID, and returns it
+// along with whether or not it was found.
+func (m Menu) FindMeal(id string) (Meal, bool) {
+	for _, c := range m.Categories {
+		for _, meal := range c.Meals {
+			if meal.ID == id {
+				return meal, true
+			}
+		}
+	}
+	return Meal{}, false
+}
+
+// GetMenu is used to retrieve a merchant's menu from the Favor API.
+func (c Client) GetMenu(merchantID string) (Menu, error) {
+	urlParams := map[string]string{}
+	uri := c.BuildURL(fmt.Sprintf("merchant/%v/menu", merchantID), urlParams)
+	menuData, err := c.makeAPIRequest("get", uri)
+	if err != nil {
+		return Menu{}, err
+	}
+	mr := struct {
+		Menu Menu `json:"menu"`
+	}{}
+
+	err = json.Unmarshal(menuData, &mr)
+	if err != nil {
+		return Menu{}, err
+	}
+
+	// Meals don't always know which category they came from, so we fill
+	// that in here so that RequestFavors built from them are correct.
+	for i, category := range mr.Menu.Categories {
+		for j, meal := range category.Meals {
+			if meal.CategoryID == "" {
+				mr.Menu.Categories[i].Meals[j].CategoryID = category.ID
+			}
+		}
+	}
+	if mr.Menu.MerchantID == "" {
+		mr.Menu.MerchantID = merchantID
+	}
+	return mr.Menu, nil
+}
+
+// RequestFavorForMeals builds a RequestFavor for the merchant out of the provided
+// meals. The Favor API only accepts one meal ID per favor, so the IDs of the
+// first meal are used, and every meal's name is listed in Wants. Delivery
+// information is left for the caller to fill in.
+func (m Merchant) RequestFavorForMeals(meals ...Meal) (RequestFavor, error) {
+	if len(meals) == 0 {
+		return RequestFavor{}, fmt.Errorf("At least one meal is required to build a favor request.")
+	}
+
+	merchantID, err := strconv.Atoi(m.ID)
+	if err != nil {
+		return RequestFavor{}, fmt.Errorf("Error parsing merchant ID %v into integer!:\n%v", m.ID, err)
+	}
+	mealID, err := strconv.Atoi(meals[0].ID)
+	if err != nil {
+		return RequestFavor{}, fmt.Errorf("Error parsing meal ID %v into integer!:\n%v", meals[0].ID, err)
+	}
+	categoryID, err := strconv.Atoi(meals[0].CategoryID)
+	if err != nil {
+		return RequestFavor{}, fmt.Errorf("Error parsing category ID %v into integer!:\n%v", meals[0].CategoryID, err)
+	}
+
+	// MarketID is omitted by some endpoints, so we don't fail if it's missing.
+	marketID, _ := strconv.Atoi(m.MarketID)
+
+	names := []string{}
+	for _, meal := range meals {
+		names = append(names, meal.Name)
+	}
+
+	rf := RequestFavor{
+		Title:            m.Name,
+		Wants:            strings.Join(names, ", "),
+		MarketID:         marketID,
+		MerchantID:       merchantID,
+		MealID:           mealID,
+		OriginMealID:     mealID,
+		OriginCategoryID: categoryID,
+	}
+	return rf, nil
+}
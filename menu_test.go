@@ -0,0 +1,110 @@
+package favor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetMenu(t *testing.T) {
+	dummyMenuResponse := `
+	{
+		"menu": {
+			"categories": [{
+				"id": "12",
+				"name": "Tacos",
+				"meals": [{
+					"id": "345",
+					"name": "Trailer Park",
+					"price": "4.75",
+					"options": [{
+						"id": "6",
+						"name": "Get it trashy",
+						"price": "0.50"
+					}]
+				}]
+			}]
+		}
+	}`
+
+	s := setupMockClient(t, respondWith(dummyMenuResponse))
+
+	expectedMenu := Menu{
+		MerchantID: "1234",
+		Categories: []MenuCategory{
+			MenuCategory{
+				ID:   "12",
+				Name: "Tacos",
+				Meals: []Meal{
+					Meal{
+						ID:         "345",
+						CategoryID: "12",
+						Name:       "Trailer Park",
+						Price:      "4.75",
+						Options: []MenuOption{
+							MenuOption{
+								ID:    "6",
+								Name:  "Get it trashy",
+								Price: "0.50",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	actualMenu, err := s.GetMenu("1234")
+	if err != nil {
+		t.Errorf("GetMenu failed with the following error: %v", err)
+	}
+
+	if !reflect.DeepEqual(expectedMenu, actualMenu) {
+		t.Errorf("Retrieved Menu differs from expected result.\n")
+		t.Errorf("Expected:\n%v+ \n", expectedMenu)
+		t.Errorf("Received:\n%v+ \n", actualMenu)
+	}
+
+	meal, found := actualMenu.FindMeal("345")
+	if !found || meal.Name != "Trailer Park" {
+		t.Errorf("FindMeal failed to find a meal that exists on the menu")
+	}
+	_, found = actualMenu.FindMeal("999")
+	if found {
+		t.Errorf("FindMeal found a meal that doesn't exist on the menu")
+	}
+}
+
+func TestRequestFavorForMeals(t *testing.T) {
+	m := Merchant{
+		ID:       "1234",
+		MarketID: "1",
+		Name:     "Farts McGregor's Corntopia",
+	}
+	meals := []Meal{
+		Meal{ID: "345", CategoryID: "12", Name: "Trailer Park"},
+		Meal{ID: "346", CategoryID: "13", Name: "Queso"},
+	}
+
+	expected := RequestFavor{
+		Title:            "Farts McGregor's Corntopia",
+		Wants:            "Trailer Park, Queso",
+		MarketID:         1,
+		MerchantID:       1234,
+		MealID:           345,
+		OriginMealID:     345,
+		OriginCategoryID: 12,
+	}
+	actual, err := m.RequestFavorForMeals(meals...)
+	if err != nil {
+		t.Errorf("RequestFavorForMeals failed with the following error: %v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected:\n%v+ \n", expected)
+		t.Errorf("Received:\n%v+ \n", actual)
+	}
+
+	_, err = m.RequestFavorForMeals()
+	if err == nil {
+		t.Errorf("RequestFavorForMeals should fail when no meals are provided")
+	}
+}
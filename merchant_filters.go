@@ -0,0 +1,144 @@
+package favor
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// MerchantFilter decides whether or not a merchant should be kept.
+type MerchantFilter func(Merchant) bool
+
+// MerchantComparator reports whether merchant a should come before merchant b.
+type MerchantComparator func(a, b Merchant) bool
+
+// Filter returns the merchants that pass every one of the filters, in the same
+// order they started in.
+func (m Merchants) Filter(filters ...MerchantFilter) Merchants {
+	kept := Merchants{}
+	for _, merchant := range m {
+		keep := true
+		for _, f := range filters {
+			if !f(merchant) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			kept = append(kept, merchant)
+		}
+	}
+	return kept
+}
+
+// SortBy returns a copy of the merchants sorted by the comparators. Merchants
+// that the first comparator considers equal are sorted by the second, and so on.
+func (m Merchants) SortBy(comparators ...MerchantComparator) Merchants {
+	sorted := append(Merchants{}, m...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		for _, less := range comparators {
+			switch {
+			case less(sorted[i], sorted[j]):
+				return true
+			case less(sorted[j], sorted[i]):
+				return false
+			}
+		}
+		return false
+	})
+	return sorted
+}
+
+// OpenAt keeps merchants that are open at the given time.
+func OpenAt(t time.Time) MerchantFilter {
+	return func(m Merchant) bool {
+		return m.IsOpenAt(t)
+	}
+}
+
+// InFranchise keeps merchants belonging to the given franchise.
+func InFranchise(franchiseID string) MerchantFilter {
+	return func(m Merchant) bool {
+		return m.FranchiseID == franchiseID
+	}
+}
+
+// InMarket keeps merchants in the given market.
+func InMarket(marketID string) MerchantFilter {
+	return func(m Merchant) bool {
+		return m.MarketID == marketID
+	}
+}
+
+// NotCarOnly keeps merchants that don't require a car to deliver from, for
+// when the runners are on bikes.
+func NotCarOnly() MerchantFilter {
+	return func(m Merchant) bool {
+		return m.IsCarOnly != "1"
+	}
+}
+
+// ExpandedMenuOnly keeps merchants that have a menu available through GetMenu.
+func ExpandedMenuOnly() MerchantFilter {
+	return func(m Merchant) bool {
+		return m.HasExpandedMenu == "1"
+	}
+}
+
+// WithinMeters keeps merchants no more than meters away from a point.
+func WithinMeters(from LatLng, meters float64) MerchantFilter {
+	return func(m Merchant) bool {
+		d, err := m.DistanceFrom(from)
+		return err == nil && d <= meters
+	}
+}
+
+// NameMatches keeps merchants whose name contains every character of the query,
+// in order, ignoring case, spaces, punctuation and accents, the same way
+// SearchMerchants does. So "trchy" finds "Torchy's Tacos", but "ychrot" doesn't.
+func NameMatches(query string) MerchantFilter {
+	q := []rune(strings.Join(searchTokens(query), ""))
+	return func(m Merchant) bool {
+		i := 0
+		for _, r := range strings.Join(searchTokens(m.Name), "") {
+			if i < len(q) && r == q[i] {
+				i++
+			}
+		}
+		return i == len(q)
+	}
+}
+
+// ByName sorts merchants alphabetically.
+func ByName() MerchantComparator {
+	return func(a, b Merchant) bool {
+		return a.Name < b.Name
+	}
+}
+
+// ByDistance sorts merchants from closest to farthest from a point. Merchants
+// without usable coordinates go last.
+func ByDistance(from LatLng) MerchantComparator {
+	return func(a, b Merchant) bool {
+		da, aErr := a.DistanceFrom(from)
+		db, bErr := b.DistanceFrom(from)
+		if aErr != nil || bErr != nil {
+			return aErr == nil && bErr != nil
+		}
+		return da < db
+	}
+}
+
+// ByServerDistance sorts merchants by the Distance the server reported.
+func ByServerDistance() MerchantComparator {
+	return func(a, b Merchant) bool {
+		return a.Distance < b.Distance
+	}
+}
+
+// OpenFirst sorts merchants that are open at the given time ahead of ones that aren't.
+func OpenFirst(t time.Time) MerchantComparator {
+	return func(a, b Merchant) bool {
+		return a.IsOpenAt(t) && !b.IsOpenAt(t)
+	}
+}
@@ -0,0 +1,45 @@
+package favor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func merchantNames(ms Merchants) []string {
+	names := []string{}
+	for _, m := range ms {
+		names = append(names, m.Name)
+	}
+	return names
+}
+
+var filterableMerchants = Merchants{
+	Merchant{ID: "1", Name: "Torchy's Tacos", FranchiseID: "10", MarketID: "1", Lat: "30.2500", Lng: "-97.7500", HasExpandedMenu: "1"},
+	Merchant{ID: "2", Name: "Torchy's Tacos", FranchiseID: "10", MarketID: "1", Lat: "30.2672", Lng: "-97.7431", IsCarOnly: "1"},
+	Merchant{ID: "3", Name: "Farts McGregor's Corntopia", MarketID: "1", Lat: "30.3000", Lng: "-97.7000"},
+	Merchant{ID: "4", Name: "Big Tex Corny Dogs", MarketID: "2", Lat: "32.7767", Lng: "-96.7970", HasExpandedMenu: "1"},
+}
+
+func TestMerchantsFilter(t *testing.T) {
+	assert.Equal(t, []string{"Torchy's Tacos", "Torchy's Tacos"}, merchantNames(filterableMerchants.Filter(InFranchise("10"))))
+	assert.Len(t, filterableMerchants.Filter(InMarket("1"), NotCarOnly()), 2)
+	assert.Len(t, filterableMerchants.Filter(ExpandedMenuOnly(), InMarket("1")), 1)
+	assert.Equal(t, []string{"Torchy's Tacos", "Torchy's Tacos"}, merchantNames(filterableMerchants.Filter(NameMatches("torchys"))))
+	assert.Equal(t, []string{"Farts McGregor's Corntopia", "Big Tex Corny Dogs"}, merchantNames(filterableMerchants.Filter(NameMatches("CORN"))))
+	assert.Len(t, filterableMerchants.Filter(NameMatches("ysohcrot")), 0)
+	assert.Len(t, Merchants{{Name: "Jalapeño Café"}}.Filter(NameMatches("jalapeno cafe")), 1, "Accents should be folded like they are in SearchMerchants")
+	assert.Len(t, filterableMerchants.Filter(WithinMeters(austin, 10000)), 3)
+}
+
+func TestMerchantsSortBy(t *testing.T) {
+	sorted := filterableMerchants.SortBy(ByName(), ByDistance(austin))
+	ids := []string{}
+	for _, m := range sorted {
+		ids = append(ids, m.ID)
+	}
+	assert.Equal(t, []string{"4", "3", "2", "1"}, ids)
+
+	chained := filterableMerchants.Filter(NotCarOnly()).SortBy(ByDistance(austin))
+	assert.Equal(t, []string{"Torchy's Tacos", "Farts McGregor's Corntopia", "Big Tex Corny Dogs"}, merchantNames(chained))
+}
@@ -42,7 +42,10 @@ type MerchantHours struct {
 
 func (m MerchantHoursResponse) buildTimesFromHours() (map[int][]MerchantHours, error) {
 	hours := map[int][]MerchantHours{}
+	// The times land in the current week, starting from Sunday, so that
+	// their weekdays line up with Favor's day indices.
 	now := time.Now()
+	sunday := now.AddDate(0, 0, -int(now.Weekday()))
 
 	for _, d := range m.Days {
 		newDay := MerchantHours{}
@@ -54,11 +57,7 @@ func (m MerchantHoursResponse) buildTimesFromHours() (map[int][]MerchantHours, e
 			closeString := o.End
 			closeDay := day
 			if strings.HasPrefix(o.End, "+") {
-				if closeDay == 6 {
-					closeDay = 0
-				} else {
-					closeDay++
-				}
+				closeDay++
 				closeString = strings.TrimPrefix(closeString, "+")
 			}
 
@@ -73,20 +72,50 @@ func (m MerchantHoursResponse) buildTimesFromHours() (map[int][]MerchantHours, e
 				return nil, fmt.Errorf("Error parsing close %v into integer!:\n%v", closeString, err)
 			}
 
-			newDay.Open = time.Date(now.Year(), now.Month(), day, openHour, openMinute, 0, 0, time.UTC)
-			newDay.Close = time.Date(now.Year(), now.Month(), closeDay, closeHour, closeMinute, 0, 0, time.UTC)
+			newDay.Open = time.Date(sunday.Year(), sunday.Month(), sunday.Day()+day, openHour, openMinute, 0, 0, time.UTC)
+			newDay.Close = time.Date(sunday.Year(), sunday.Month(), sunday.Day()+closeDay, closeHour, closeMinute, 0, 0, time.UTC)
 			hours[day] = append(hours[day], newDay)
 		}
 	}
 	return hours, nil
 }
 
+// minutesIntoWeek figures out how far into the week t is, counting from
+// midnight on Sunday. Only the weekday and time of day matter, so hours the
+// API sends as real dates work just as well as the ones buildTimesFromHours makes.
+func minutesIntoWeek(t time.Time) int {
+	return int(t.Weekday())*24*60 + t.Hour()*60 + t.Minute()
+}
+
+// String renders the hours in a human friendly way, like "Sunday 07:00 - Monday 03:00"
+func (h MerchantHours) String() string {
+	opens, closes := minutesIntoWeek(h.Open), minutesIntoWeek(h.Close)
+	return fmt.Sprintf("%v %02d:%02d - %v %02d:%02d",
+		time.Weekday(opens/(24*60)), opens/60%24, opens%60,
+		time.Weekday(closes/(24*60)), closes/60%24, closes%60)
+}
+
 // IsOpenAt is a helper function to determine if a merchant
 // is available for placing orders at a given time. Possible
 // usage would be something like m.IsOpenAt(time.Now())
-// func (m Merchant) IsOpenAt(t time.Time) bool {
-// 	return false
-// }
+// I'm assuming Favor's day indices line up with time.Weekday,
+// meaning 0 is Sunday, and that t is in the merchant's time zone.
+// Merchants with no hours are assumed to be closed.
+func (m Merchant) IsOpenAt(t time.Time) bool {
+	const week = 7 * 24 * 60
+	now := minutesIntoWeek(t)
+
+	for _, h := range m.Hours {
+		opens, closes := minutesIntoWeek(h.Open), minutesIntoWeek(h.Close)
+		if closes < opens {
+			closes += week
+		}
+		if (now >= opens && now < closes) || (now+week >= opens && now+week < closes) {
+			return true
+		}
+	}
+	return false
+}
 
 // Merchant describes restauraunts or places of business that cooperate
 // with Favor, to my understanding. Favor will process any request you
@@ -108,6 +137,7 @@ type Merchant struct {
 	Lat             string          `json:"lat,omitempty"`
 	Lng             string          `json:"lng,omitempty"`
 	IsCarOnly       string          `json:"is_car_only,omitempty"`
+	Cuisine         string          `json:"cuisine,omitempty"`
 }
 
 // Merchants is a container struct set up so that we
@@ -126,11 +156,24 @@ func (m Merchants) Swap(i, j int) {
 	m[i], m[j] = m[j], m[i]
 }
 
+func (c Client) merchantURL(id string) string {
+	urlParams := map[string]string{}
+	return c.BuildURL(fmt.Sprintf("merchant/%v", id), urlParams)
+}
+
+func (c Client) merchantsURL(lat, long float64) string {
+	urlParams := map[string]string{
+		"lat":             strconv.FormatFloat(lat, 'f', -1, 64),
+		"lng":             strconv.FormatFloat(long, 'f', -1, 64),
+		"location_source": "gps",
+	}
+	return c.BuildURL("merchants", urlParams)
+}
+
 // GetMerchant is used to retrieve a single merchant from the Favor API.
 func (c Client) GetMerchant(id string) (Merchant, error) {
-	urlParams := map[string]string{}
-	uri := c.BuildURL(fmt.Sprintf("merchant/%v", id), urlParams)
-	merchantData, err := c.makeAPIRequest("get", uri)
+	uri := c.merchantURL(id)
+	merchantData, err := c.makeCachedAPIRequest(CacheMerchant, uri)
 	if err != nil {
 		return Merchant{}, err
 	}
@@ -147,13 +190,11 @@ func (c Client) GetMerchant(id string) (Merchant, error) {
 
 // GetMerchants is used to retrieve Merchants from the Favor API.
 func (c Client) GetMerchants(lat, long float64) ([]Merchant, error) {
-	urlParams := map[string]string{
-		"lat":             strconv.FormatFloat(lat, 'f', -1, 64),
-		"lng":             strconv.FormatFloat(long, 'f', -1, 64),
-		"location_source": "gps",
+	uri := c.merchantsURL(lat, long)
+	merchantData, err := c.makeCachedAPIRequest(CacheMerchants, uri)
+	if err != nil {
+		return nil, err
 	}
-	uri := c.BuildURL("merchants", urlParams)
-	merchantData, err := c.makeAPIRequest("get", uri)
 	mr := struct {
 		Merchants Merchants `json:"merchants"`
 	}{}
@@ -1,19 +1,13 @@
 package favor
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
 )
 
 func TestGetMerchant(t *testing.T) {
-	s, err := New(dummyToken)
-	if err != nil {
-		t.Errorf("Constructor failed with the following error: %v", err)
-		t.FailNow()
-	}
-	s.Secure = false
-
 	dummyMerchantResponse := `
 	{
 		"merchant": {
@@ -33,10 +27,7 @@ func TestGetMerchant(t *testing.T) {
 		}
 	}`
 
-	server, client := setupMockClient(dummyMerchantResponse)
-	defer server.Close()
-
-	s.Client = client
+	s := setupMockClient(t, respondWith(dummyMerchantResponse))
 
 	expectedMerchant := Merchant{
 		ID:              "1234",
@@ -67,13 +58,6 @@ func TestGetMerchant(t *testing.T) {
 }
 
 func TestGetMerchants(t *testing.T) {
-	s, err := New(dummyToken)
-	if err != nil {
-		t.Errorf("Constructor failed with the following error: %v", err)
-		t.FailNow()
-	}
-	s.Secure = false
-
 	dummyMerchantResponse := `
 	{
 		"merchants": [{
@@ -93,10 +77,7 @@ func TestGetMerchants(t *testing.T) {
 		}]
 	}`
 
-	server, client := setupMockClient(dummyMerchantResponse)
-	defer server.Close()
-
-	s.Client = client
+	s := setupMockClient(t, respondWith(dummyMerchantResponse))
 
 	expectedMerchants := []Merchant{
 		Merchant{
@@ -150,6 +131,7 @@ func merchantHoursAreEqual(a, b map[int][]MerchantHours, t *testing.T) bool {
 
 func TestBuildTimesFromHours(t *testing.T) {
 	now := time.Now()
+	sunday := now.AddDate(0, 0, -int(now.Weekday()))
 
 	simple := MerchantHoursResponse{
 		Days: []string{"0"},
@@ -163,8 +145,8 @@ func TestBuildTimesFromHours(t *testing.T) {
 	expectedSimple := map[int][]MerchantHours{
 		0: []MerchantHours{
 			MerchantHours{
-				Open:  time.Date(now.Year(), now.Month(), 0, 7, 0, 0, 0, time.UTC),
-				Close: time.Date(now.Year(), now.Month(), 0, 15, 0, 0, 0, time.UTC),
+				Open:  time.Date(sunday.Year(), sunday.Month(), sunday.Day(), 7, 0, 0, 0, time.UTC),
+				Close: time.Date(sunday.Year(), sunday.Month(), sunday.Day(), 15, 0, 0, 0, time.UTC),
 			},
 		},
 	}
@@ -188,8 +170,8 @@ func TestBuildTimesFromHours(t *testing.T) {
 	expectedComplicated := map[int][]MerchantHours{
 		0: []MerchantHours{
 			MerchantHours{
-				Open:  time.Date(now.Year(), now.Month(), 0, 7, 0, 0, 0, time.UTC),
-				Close: time.Date(now.Year(), now.Month(), 1, 3, 0, 0, 0, time.UTC),
+				Open:  time.Date(sunday.Year(), sunday.Month(), sunday.Day(), 7, 0, 0, 0, time.UTC),
+				Close: time.Date(sunday.Year(), sunday.Month(), sunday.Day()+1, 3, 0, 0, 0, time.UTC),
 			},
 		},
 	}
@@ -231,12 +213,12 @@ func TestBuildTimesFromHours(t *testing.T) {
 	expectedTwoOpenings := map[int][]MerchantHours{
 		0: []MerchantHours{
 			MerchantHours{
-				Open:  time.Date(now.Year(), now.Month(), 0, 7, 0, 0, 0, time.UTC),
-				Close: time.Date(now.Year(), now.Month(), 0, 15, 0, 0, 0, time.UTC),
+				Open:  time.Date(sunday.Year(), sunday.Month(), sunday.Day(), 7, 0, 0, 0, time.UTC),
+				Close: time.Date(sunday.Year(), sunday.Month(), sunday.Day(), 15, 0, 0, 0, time.UTC),
 			},
 			MerchantHours{
-				Open:  time.Date(now.Year(), now.Month(), 0, 18, 0, 0, 0, time.UTC),
-				Close: time.Date(now.Year(), now.Month(), 0, 21, 0, 0, 0, time.UTC),
+				Open:  time.Date(sunday.Year(), sunday.Month(), sunday.Day(), 18, 0, 0, 0, time.UTC),
+				Close: time.Date(sunday.Year(), sunday.Month(), sunday.Day(), 21, 0, 0, 0, time.UTC),
 			},
 		},
 	}
@@ -248,3 +230,100 @@ func TestBuildTimesFromHours(t *testing.T) {
 		t.Errorf("Two openings parsing produced unexpected results")
 	}
 }
+
+func TestMerchantIsOpenAt(t *testing.T) {
+	// Sunday through Saturday, 7 AM to 3 AM the next day
+	hoursResponse := MerchantHoursResponse{
+		Days: []string{"0", "1", "2", "3", "4", "5", "6"},
+		Open: []HoursOpen{
+			HoursOpen{
+				Start: "0700",
+				End:   "+0300",
+			},
+		},
+	}
+	hours, err := hoursResponse.buildTimesFromHours()
+	if err != nil {
+		t.Errorf("Parsing hours failed with error:\n%v", err)
+	}
+
+	m := Merchant{Name: "Farts McGregor's Corntopia"}
+	for _, h := range hours {
+		m.Hours = append(m.Hours, h...)
+	}
+
+	sundayMorning := time.Date(2017, time.January, 1, 10, 0, 0, 0, time.UTC)
+	sundayLateNight := time.Date(2017, time.January, 2, 2, 30, 0, 0, time.UTC)
+	saturdayLateNight := time.Date(2017, time.January, 8, 2, 30, 0, 0, time.UTC)
+	mondayDawn := time.Date(2017, time.January, 2, 5, 0, 0, 0, time.UTC)
+
+	if !m.IsOpenAt(sundayMorning) {
+		t.Errorf("Merchant should be open on Sunday morning")
+	}
+	if !m.IsOpenAt(sundayLateNight) {
+		t.Errorf("Merchant should still be open late Sunday night")
+	}
+	if !m.IsOpenAt(saturdayLateNight) {
+		t.Errorf("Merchant should still be open late Saturday night")
+	}
+	if m.IsOpenAt(mondayDawn) {
+		t.Errorf("Merchant should be closed at dawn")
+	}
+	if (Merchant{}).IsOpenAt(sundayMorning) {
+		t.Errorf("Merchants without hours should be considered closed")
+	}
+}
+
+func TestMerchantHoursString(t *testing.T) {
+	hoursResponse := MerchantHoursResponse{
+		Days: []string{"6"},
+		Open: []HoursOpen{
+			HoursOpen{
+				Start: "0700",
+				End:   "+0330",
+			},
+		},
+	}
+	hours, err := hoursResponse.buildTimesFromHours()
+	if err != nil {
+		t.Errorf("Parsing hours failed with error:\n%v", err)
+	}
+
+	expected := "Saturday 07:00 - Sunday 03:30"
+	if actual := hours[6][0].String(); actual != expected {
+		t.Errorf("Expected hours to render as %q, instead got %q", expected, actual)
+	}
+}
+
+func TestMerchantHoursFromAPIDates(t *testing.T) {
+	// Hours that come back from the API are real dates, like this Monday
+	// from 7 AM to 9 PM, rather than the ones buildTimesFromHours makes up.
+	body := `{"id": "1", "name": "Kerbey Lane", "hours": [{"Open": "2026-10-19T07:00:00Z", "Close": "2026-10-19T21:00:00Z"}]}`
+	m := Merchant{}
+	if err := json.Unmarshal([]byte(body), &m); err != nil {
+		t.Fatalf("Parsing the merchant failed with error:\n%v", err)
+	}
+
+	expected := "Monday 07:00 - Monday 21:00"
+	if actual := m.Hours[0].String(); actual != expected {
+		t.Errorf("Expected hours to render as %q, instead got %q", expected, actual)
+	}
+
+	mondayNoon := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	nextMondayNoon := time.Date(2026, time.October, 26, 12, 0, 0, 0, time.UTC)
+	mondayNight := time.Date(2026, time.October, 19, 22, 0, 0, 0, time.UTC)
+	sundayNoon := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+
+	if !m.IsOpenAt(mondayNoon) {
+		t.Errorf("Merchant should be open at noon on Monday")
+	}
+	if !m.IsOpenAt(nextMondayNoon) {
+		t.Errorf("Merchant should be open at noon every Monday, not just the one in its hours")
+	}
+	if m.IsOpenAt(mondayNight) {
+		t.Errorf("Merchant should be closed on Monday night")
+	}
+	if m.IsOpenAt(sundayNoon) {
+		t.Errorf("Merchant should be closed on Sunday")
+	}
+}
@@ -0,0 +1,176 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Writer writes messages to an io.Writer, stdout if Out isn't set.
+type Writer struct {
+	Out io.Writer
+
+	lock sync.Mutex
+}
+
+// Notify writes the message.
+func (w *Writer) Notify(ctx context.Context, m Message) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	out := w.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	_, err := fmt.Fprintf(out, "%v\n\n%v\n", m.Subject, m.Body)
+	return err
+}
+
+// ChatWebhook posts messages to a chat service's incoming webhook. Most of
+// them, Slack and Mattermost included, accept a JSON body with a "text" field,
+// which is all this sends.
+type ChatWebhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// Notify posts the message.
+func (c ChatWebhook) Notify(ctx context.Context, m Message) error {
+	body, err := json.Marshal(map[string]string{"text": fmt.Sprintf("*%v*\n%v", m.Subject, m.Body)})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Posting to the chat webhook failed and returned this error:\n %v", err)
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("The chat webhook responded with %v", res.Status)
+	}
+	return nil
+}
+
+// SMTP emails messages. If To is empty, the message goes to the email address
+// of the favor's customer, which is usually who ought to hear about it. Auth
+// can be left nil for servers that don't want it. Timeout is how long sending
+// one message can take, 30 seconds if it isn't set, or less if the context
+// passed to Notify runs out first.
+type SMTP struct {
+	Addr    string
+	From    string
+	To      []string
+	Auth    smtp.Auth
+	Timeout time.Duration
+}
+
+// Notify sends the message.
+func (s SMTP) Notify(ctx context.Context, m Message) error {
+	to := s.To
+	if len(to) == 0 && m.Favor.Customer.Email != "" {
+		to = []string{m.Favor.Customer.Email}
+	}
+	if len(to) == 0 {
+		return fmt.Errorf("There's nobody to email about favor %v.", m.Favor.ID)
+	}
+
+	msg := &bytes.Buffer{}
+	fmt.Fprintf(msg, "From: %v\r\n", s.From)
+	fmt.Fprintf(msg, "To: %v\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(msg, "Subject: %v\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(msg, "Date: %v\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprint(msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprint(msg, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprint(msg, strings.Replace(m.Body, "\n", "\r\n", -1))
+
+	if err := s.send(ctx, to, msg.Bytes()); err != nil {
+		return fmt.Errorf("Emailing %v failed and returned this error:\n %v", strings.Join(to, ", "), err)
+	}
+	return nil
+}
+
+// send does what smtp.SendMail does, except that it gives up when the context
+// is done or the timeout runs out, instead of waiting on the server forever.
+func (s SMTP) send(ctx context.Context, to []string, msg []byte) error {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	// The deadline covers running out of time, but not being cancelled.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.Auth != nil {
+		if ok, _ := c.Extension("AUTH"); ok {
+			if err := c.Auth(s.Auth); err != nil {
+				return err
+			}
+		}
+	}
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	for _, address := range to {
+		if err := c.Rcpt(address); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
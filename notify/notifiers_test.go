@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/notify/smtptest"
+)
+
+var testMessage = Message{Subject: "Your Torchy's Tacos has arrived", Body: "Speedy just delivered Torchy's Tacos.\n", Favor: deliveredFavor}
+
+func TestWriter(t *testing.T) {
+	out := &bytes.Buffer{}
+	assert.Nil(t, (&Writer{Out: out}).Notify(context.Background(), testMessage))
+	assert.Equal(t, "Your Torchy's Tacos has arrived\n\nSpeedy just delivered Torchy's Tacos.\n\n", out.String())
+}
+
+func TestChatWebhook(t *testing.T) {
+	var posted map[string]string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&posted)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	c := ChatWebhook{URL: server.URL}
+	assert.Nil(t, c.Notify(context.Background(), testMessage))
+	assert.Contains(t, posted["text"], "*Your Torchy's Tacos has arrived*")
+	assert.Contains(t, posted["text"], "Speedy just delivered")
+
+	status = http.StatusNotFound
+	assert.NotNil(t, c.Notify(context.Background(), testMessage))
+}
+
+func TestSMTP(t *testing.T) {
+	server, err := smtptest.NewServer()
+	if err != nil {
+		t.Errorf("Starting the SMTP stand-in failed with the following error: %v", err)
+		t.FailNow()
+	}
+	defer server.Close()
+
+	s := SMTP{Addr: server.Addr, From: "favor@example.com"}
+	assert.Nil(t, s.Notify(context.Background(), testMessage))
+	messages := server.Messages()
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, "favor@example.com", messages[0].From)
+		assert.Equal(t, []string{"greg@example.com"}, messages[0].To, "The customer should get the email when nobody else is named")
+		assert.Contains(t, messages[0].Data, "Subject: Your Torchy's Tacos has arrived")
+		assert.Contains(t, messages[0].Data, "Speedy just delivered Torchy's Tacos.")
+	}
+
+	s.To = []string{"finance@example.com", "bots@example.com"}
+	assert.Nil(t, s.Notify(context.Background(), testMessage))
+	assert.Equal(t, []string{"finance@example.com", "bots@example.com"}, server.Messages()[1].To)
+
+	anonymous := testMessage
+	anonymous.Favor = favor.Favor{ID: "1"}
+	s.To = nil
+	assert.NotNil(t, s.Notify(context.Background(), anonymous), "Emailing nobody should be an error")
+}
+
+func TestSMTPGivesUp(t *testing.T) {
+	// a server that takes the connection and then never says a word
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Errorf("Listening failed with the following error: %v", err)
+		t.FailNow()
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	s := SMTP{Addr: listener.Addr().String(), From: "favor@example.com"}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	assert.NotNil(t, s.Notify(ctx, testMessage))
+	assert.True(t, time.Since(started) < 5*time.Second, "Notify should give up when the context is done")
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	started = time.Now()
+	assert.NotNil(t, s.Notify(ctx, testMessage))
+	assert.True(t, time.Since(started) < 5*time.Second, "Notify should give up when the context is cancelled")
+
+	s.Timeout = 50 * time.Millisecond
+	started = time.Now()
+	assert.NotNil(t, s.Notify(context.Background(), testMessage))
+	assert.True(t, time.Since(started) < 5*time.Second, "Notify should give up when the timeout runs out")
+}
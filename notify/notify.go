@@ -0,0 +1,195 @@
+// Package notify lets people know when something happens to their favor, most
+// importantly when it shows up. Messages are written with text/template, and
+// sent through any number of Notifiers: email, a chat webhook, or just a
+// terminal.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// Message is a notification that's ready to go. Favor is the favor it's about,
+// so that notifiers can work out who to send it to.
+type Message struct {
+	Subject string
+	Body    string
+	Favor   favor.Favor
+}
+
+// Notifier sends messages somewhere.
+type Notifier interface {
+	Notify(ctx context.Context, m Message) error
+}
+
+// TemplateData is what templates are executed with. Amounts is the receipt
+// in cents, and the template functions include dollars, which formats them.
+type TemplateData struct {
+	Event   favor.EventType
+	Favor   favor.Favor
+	Runner  favor.User
+	Receipt favor.Receipt
+	Amounts favor.ReceiptAmounts
+}
+
+var templateFuncs = template.FuncMap{
+	"dollars": func(cents int64) string {
+		return "$" + favor.FormatCents(cents)
+	},
+	"name": func(u favor.User) string {
+		return strings.TrimSpace(u.Forename + " " + u.Surname)
+	},
+}
+
+// Template is how a message gets written.
+type Template struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// NewTemplate parses the subject and body of a message. Besides the fields of
+// TemplateData, they can use {{dollars .Amounts.Total}} to format cents and
+// {{name .Runner}} for a user's full name.
+func NewTemplate(subject, body string) (Template, error) {
+	s, err := template.New("subject").Funcs(templateFuncs).Parse(subject)
+	if err != nil {
+		return Template{}, err
+	}
+	b, err := template.New("body").Funcs(templateFuncs).Parse(body)
+	if err != nil {
+		return Template{}, err
+	}
+	return Template{subject: s, body: b}, nil
+}
+
+// MustTemplate is NewTemplate for templates that are known to be fine, and
+// panics if they aren't.
+func MustTemplate(subject, body string) Template {
+	t, err := NewTemplate(subject, body)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// DeliveredTemplate is the message sent when a favor is delivered, unless
+// somebody has a better idea.
+var DeliveredTemplate = MustTemplate(
+	`Your {{.Favor.Title}} has arrived`,
+	`{{with name .Runner}}{{.}}{{else}}Your runner{{end}} just delivered {{.Favor.Title}}.
+{{if .Amounts.Total}}
+Price:    {{dollars .Amounts.Price}}
+Tip:      {{dollars .Amounts.Tip}}
+Delivery: {{dollars .Amounts.DeliveryCharge}}
+Card fee: {{dollars .Amounts.CcFeeAmount}}
+Total:    {{dollars .Amounts.Total}}
+{{end}}`)
+
+// Render writes the message for an event.
+func (t Template) Render(e favor.FavorEvent) (Message, error) {
+	data := TemplateData{Event: e.Type, Favor: e.Favor, Runner: e.Favor.Runner, Receipt: e.Favor.Receipt}
+	amounts, err := e.Favor.Receipt.Amounts()
+	if err != nil {
+		return Message{}, err
+	}
+	data.Amounts = amounts
+
+	subject, body := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := t.subject.Execute(subject, data); err != nil {
+		return Message{}, err
+	}
+	if err := t.body.Execute(body, data); err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    strings.TrimSpace(body.String()) + "\n",
+		Favor:   e.Favor,
+	}, nil
+}
+
+// Subscription sends a templated message to some notifiers whenever a favor
+// event matches. Events is which types of event it's interested in, or all of
+// them if it's empty, and When can narrow it down further.
+type Subscription struct {
+	Events    []favor.EventType
+	When      func(favor.FavorEvent) bool
+	Template  Template
+	Notifiers []Notifier
+}
+
+func (s Subscription) matches(e favor.FavorEvent) bool {
+	if len(s.Events) > 0 {
+		wanted := false
+		for _, t := range s.Events {
+			wanted = wanted || t == e.Type
+		}
+		if !wanted {
+			return false
+		}
+	}
+	return s.When == nil || s.When(e)
+}
+
+// OnDelivered is a subscription for favors that have been delivered, as
+// opposed to the other ways a favor can be finished, like being cancelled.
+func OnDelivered(t Template, notifiers ...Notifier) Subscription {
+	return Subscription{
+		Events: []favor.EventType{favor.EventFinished},
+		When: func(e favor.FavorEvent) bool {
+			stage := strings.ToLower(e.Favor.Stage)
+			return stage == "delivered" || stage == "completed" || stage == "complete"
+		},
+		Template:  t,
+		Notifiers: notifiers,
+	}
+}
+
+// Notifications routes favor events to the subscriptions that want them.
+type Notifications struct {
+	Subscriptions []Subscription
+}
+
+// Handle sends every message an event calls for. A notifier failing doesn't
+// stop the others from being tried, but the failures are all returned.
+func (n Notifications) Handle(ctx context.Context, e favor.FavorEvent) error {
+	failures := []string{}
+	for _, s := range n.Subscriptions {
+		if !s.matches(e) {
+			continue
+		}
+		m, err := s.Template.Render(e)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("rendering: %v", err))
+			continue
+		}
+		for _, notifier := range s.Notifiers {
+			if err := notifier.Notify(ctx, m); err != nil {
+				failures = append(failures, err.Error())
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("Sending notifications for favor %v failed:\n%v", e.Favor.ID, strings.Join(failures, "\n"))
+	}
+	return nil
+}
+
+// Run follows the tracker's favors and sends notifications for them until the
+// context is done. Notifications that fail are reported to onError, if it's
+// set, and otherwise ignored, so one bad mail server doesn't stop everything.
+// Like Dispatcher.Run in the webhooks package, it needs the tracker to itself;
+// call Handle from your own tracker.Run to share one.
+func (n Notifications) Run(ctx context.Context, tracker *favor.FavorTracker, onError func(error)) error {
+	return tracker.Run(ctx, func(e favor.FavorEvent) error {
+		if err := n.Handle(ctx, e); err != nil && onError != nil {
+			onError(err)
+		}
+		return nil
+	})
+}
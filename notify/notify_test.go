@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+var deliveredFavor = favor.Favor{
+	ID:       "9876",
+	Title:    "Torchy's Tacos",
+	Stage:    "delivered",
+	Customer: favor.User{Forename: "Greg", Email: "greg@example.com"},
+	Runner:   favor.User{Forename: "Speedy", Surname: "Gonzales"},
+	Receipt:  favor.Receipt{Price: "10.00", Tip: "2.00", DeliveryCharge: "1.50", CcFeeAmount: "0.35"},
+}
+
+// recorder is a Notifier that remembers what it was asked to send.
+type recorder struct {
+	messages []Message
+	err      error
+}
+
+func (r *recorder) Notify(ctx context.Context, m Message) error {
+	r.messages = append(r.messages, m)
+	return r.err
+}
+
+func TestDeliveredTemplate(t *testing.T) {
+	m, err := DeliveredTemplate.Render(favor.FavorEvent{Type: favor.EventFinished, Favor: deliveredFavor})
+	assert.Nil(t, err)
+	assert.Equal(t, "Your Torchy's Tacos has arrived", m.Subject)
+	assert.Contains(t, m.Body, "Speedy Gonzales just delivered Torchy's Tacos.")
+	assert.Contains(t, m.Body, "Total:    $13.85")
+
+	unpaid := deliveredFavor
+	unpaid.Runner, unpaid.Receipt = favor.User{}, favor.Receipt{}
+	m, err = DeliveredTemplate.Render(favor.FavorEvent{Type: favor.EventFinished, Favor: unpaid})
+	assert.Nil(t, err)
+	assert.Equal(t, "Your runner just delivered Torchy's Tacos.\n", m.Body)
+
+	_, err = NewTemplate("{{.Favor.Nope", "")
+	assert.NotNil(t, err)
+}
+
+func TestNotifications(t *testing.T) {
+	delivered, everything := &recorder{}, &recorder{}
+	n := Notifications{Subscriptions: []Subscription{
+		OnDelivered(DeliveredTemplate, delivered),
+		{Template: MustTemplate("{{.Event}}", "{{.Favor.Stage}}"), Notifiers: []Notifier{everything}},
+	}}
+
+	cancelled := deliveredFavor
+	cancelled.Stage = "cancelled"
+	assert.Nil(t, n.Handle(context.Background(), favor.FavorEvent{Type: favor.EventFinished, Favor: cancelled}))
+	assert.Empty(t, delivered.messages, "Cancelled favors weren't delivered")
+	assert.Nil(t, n.Handle(context.Background(), favor.FavorEvent{Type: favor.EventFinished, Favor: deliveredFavor}))
+	assert.Len(t, delivered.messages, 1)
+	if assert.Len(t, everything.messages, 2) {
+		assert.Equal(t, "favor.finished", everything.messages[0].Subject)
+		assert.Equal(t, "cancelled\n", everything.messages[0].Body)
+	}
+
+	delivered.err = fmt.Errorf("the mail server is on fire")
+	err := n.Handle(context.Background(), favor.FavorEvent{Type: favor.EventFinished, Favor: deliveredFavor})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "on fire")
+	assert.Len(t, everything.messages, 3, "One notifier failing shouldn't stop the rest")
+}
@@ -0,0 +1,123 @@
+// Package smtptest is a stand-in SMTP server, for testing code that sends
+// email without sending any. It speaks just enough SMTP for net/smtp, and
+// keeps every message it's given.
+package smtptest
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Message is an email the server accepted.
+type Message struct {
+	From string
+	To   []string
+	Data string
+}
+
+// Server is a stand-in SMTP server listening on localhost.
+type Server struct {
+	Addr string
+
+	listener net.Listener
+	lock     sync.Mutex
+	messages []Message
+	wg       sync.WaitGroup
+}
+
+// NewServer starts a server on a random local port. Close it when you're done
+// with it.
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{Addr: listener.Addr().String(), listener: listener}
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serve(conn)
+		}()
+	}
+}
+
+// serve handles a single connection.
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 smtptest ready")
+
+	current := Message{}
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		argument := ""
+		if i := strings.Index(line, ":"); i >= 0 {
+			argument = strings.Trim(strings.TrimSpace(line[i+1:]), "<>")
+		}
+
+		switch verb {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250 smtptest")
+		case "MAIL":
+			current = Message{From: argument}
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			current.To = append(current.To, argument)
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 go ahead, end with <CRLF>.<CRLF>")
+			lines, err := tp.ReadDotLines()
+			if err != nil {
+				return
+			}
+			current.Data = strings.Join(lines, "\n")
+			s.lock.Lock()
+			s.messages = append(s.messages, current)
+			s.lock.Unlock()
+			current = Message{}
+			tp.PrintfLine("250 OK")
+		case "RSET":
+			current = Message{}
+			tp.PrintfLine("250 OK")
+		case "NOOP":
+			tp.PrintfLine("250 OK")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 %v isn't something I know how to do", verb)
+		}
+	}
+}
+
+// Messages returns every message the server has accepted so far.
+func (s *Server) Messages() []Message {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]Message{}, s.messages...)
+}
+
+// Close stops the server, and waits for it to finish with any connections.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
@@ -0,0 +1,310 @@
+package favor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Account is a single Favor account managed by a Pool. MinInterval is the
+// least amount of time allowed between requests made on behalf of the
+// account, and Budget is the most, in cents, that PlaceFavor is allowed to
+// spend before refusing to place any more favors. Zero means no limit for both.
+//
+// Favors don't have a receipt until somebody's gone and bought the food, so
+// accounts with a Budget also need an EstimatedCost, in cents, which is what
+// each favor counts as until its receipt shows up.
+type Account struct {
+	Name           string
+	Client         *Client
+	DefaultAddress Address
+	MinInterval    time.Duration
+	Budget         int64
+	EstimatedCost  int64
+}
+
+// AccountFavor is a Favor along with the name of the account it belongs to.
+type AccountFavor struct {
+	Account string
+	Favor   Favor
+}
+
+type poolAccount struct {
+	Account
+	next    time.Time
+	spent   int64
+	pending map[string]int64
+	unknown int
+
+	// placing is held for the whole of PlaceFavor, so that two favors can't
+	// both squeeze under the budget at once.
+	placing sync.Mutex
+}
+
+// Pool holds the Clients for several Favor accounts, keyed by name, and routes
+// calls to the right one while enforcing each account's rate limit and budget.
+type Pool struct {
+	lock     sync.Mutex
+	accounts map[string]*poolAccount
+}
+
+// NewPool is a constructor function returning an empty Pool.
+func NewPool() *Pool {
+	return &Pool{accounts: map[string]*poolAccount{}}
+}
+
+// Add puts an account into the pool.
+func (p *Pool) Add(a Account) error {
+	if a.Name == "" {
+		return fmt.Errorf("Accounts must have a name to be added to a pool.")
+	}
+	if a.Client == nil {
+		return fmt.Errorf("Account %v has no client.", a.Name)
+	}
+	if a.Budget > 0 && a.EstimatedCost <= 0 {
+		return fmt.Errorf("Account %v has a budget, so it needs an estimated cost too.", a.Name)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if _, ok := p.accounts[a.Name]; ok {
+		return fmt.Errorf("Account %v is already in the pool.", a.Name)
+	}
+	p.accounts[a.Name] = &poolAccount{Account: a, pending: map[string]int64{}}
+	return nil
+}
+
+// Names returns the names of every account in the pool, alphabetically.
+func (p *Pool) Names() []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	names := []string{}
+	for name := range p.accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Account returns the account with the given name.
+func (p *Pool) Account(name string) (Account, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	pa, ok := p.accounts[name]
+	if !ok {
+		return Account{}, fmt.Errorf("There is no account named %v in the pool.", name)
+	}
+	return pa.Account, nil
+}
+
+// Spent returns how much, in cents, the account's receipts say has been spent
+// since it was added to the pool or last reset.
+func (p *Pool) Spent(name string) int64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if pa, ok := p.accounts[name]; ok {
+		return pa.spent
+	}
+	return 0
+}
+
+// Pending returns how much, in cents, the account's favors that don't have a
+// receipt yet are expected to cost.
+func (p *Pool) Pending(name string) int64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var pending int64
+	if pa, ok := p.accounts[name]; ok {
+		for _, estimate := range pa.pending {
+			pending += estimate
+		}
+	}
+	return pending
+}
+
+// ResetSpent sets the amount spent by the account back to zero, for when a
+// new budget period begins. Favors still waiting on a receipt are forgotten
+// about too, since they were placed in the old period.
+func (p *Pool) ResetSpent(name string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if pa, ok := p.accounts[name]; ok {
+		pa.spent = 0
+		pa.pending = map[string]int64{}
+	}
+}
+
+// settle counts the receipts of any of the account's favors that were waiting
+// on one. Cancelled favors stop counting at all.
+func (p *Pool) settle(name string, favors ...Favor) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	pa, ok := p.accounts[name]
+	if !ok {
+		return
+	}
+	for _, f := range favors {
+		if _, ok := pa.pending[f.ID]; !ok {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(f.Stage), "cancel") {
+			delete(pa.pending, f.ID)
+			continue
+		}
+		if amounts, err := f.Receipt.Amounts(); err == nil && amounts.Total() > 0 {
+			pa.spent += amounts.Total()
+			delete(pa.pending, f.ID)
+		}
+	}
+}
+
+// reserve looks up an account and claims the next available slot under its rate
+// limit, returning how long the caller needs to wait before using it.
+func (p *Pool) reserve(name string) (*Client, time.Duration, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	pa, ok := p.accounts[name]
+	if !ok {
+		return nil, 0, fmt.Errorf("There is no account named %v in the pool.", name)
+	}
+
+	now := time.Now()
+	if pa.next.Before(now) {
+		pa.next = now
+	}
+	wait := pa.next.Sub(now)
+	pa.next = pa.next.Add(pa.MinInterval)
+	return pa.Client, wait, nil
+}
+
+// Do calls fn with the named account's Client, once the account's rate limit allows it.
+func (p *Pool) Do(name string, fn func(c *Client) error) error {
+	c, wait, err := p.reserve(name)
+	if err != nil {
+		return err
+	}
+	time.Sleep(wait)
+	return fn(c)
+}
+
+// GetFavor retrieves a single favor using the named account.
+func (p *Pool) GetFavor(name, id string) (Favor, error) {
+	var f Favor
+	err := p.Do(name, func(c *Client) (err error) {
+		f, err = c.GetFavor(id)
+		return err
+	})
+	if err == nil {
+		p.settle(name, f)
+	}
+	return f, err
+}
+
+// GetFavors retrieves the favors for the named account.
+func (p *Pool) GetFavors(name string) ([]Favor, error) {
+	var favors []Favor
+	err := p.Do(name, func(c *Client) (err error) {
+		favors, err = c.GetFavors()
+		return err
+	})
+	if err == nil {
+		p.settle(name, favors...)
+	}
+	return favors, err
+}
+
+// GetAllFavors retrieves the favors for every account in the pool concurrently,
+// labeled with the account they came from. If some accounts fail, the favors
+// from the rest are still returned along with an error naming the failures.
+func (p *Pool) GetAllFavors() ([]AccountFavor, error) {
+	names := p.Names()
+
+	var wg sync.WaitGroup
+	results := make([][]Favor, len(names))
+	errs := make([]error, len(names))
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			results[i], errs[i] = p.GetFavors(name)
+		}(i, name)
+	}
+	wg.Wait()
+
+	favors := []AccountFavor{}
+	failures := []string{}
+	for i, name := range names {
+		if errs[i] != nil {
+			failures = append(failures, fmt.Sprintf("%v: %v", name, errs[i]))
+			continue
+		}
+		for _, f := range results[i] {
+			favors = append(favors, AccountFavor{Account: name, Favor: f})
+		}
+	}
+	if len(failures) > 0 {
+		return favors, fmt.Errorf("Retrieving favors failed for some accounts:\n%v", strings.Join(failures, "\n"))
+	}
+	return favors, nil
+}
+
+// PlaceFavor places a favor using the named account, as long as it fits in
+// what's left of the account's budget, counting the favor at the account's
+// EstimatedCost. If the request has no street, the account's default address
+// is used. If the new favor already has a receipt, that's what's counted;
+// otherwise it counts as its estimate until GetFavor or GetFavors sees its
+// receipt.
+func (p *Pool) PlaceFavor(name string, rf RequestFavor) (Favor, error) {
+	p.lock.Lock()
+	pa, ok := p.accounts[name]
+	p.lock.Unlock()
+	if !ok {
+		return Favor{}, fmt.Errorf("There is no account named %v in the pool.", name)
+	}
+	pa.placing.Lock()
+	defer pa.placing.Unlock()
+
+	a := pa.Account
+	if a.Budget > 0 {
+		committed := p.Spent(name) + p.Pending(name)
+		if committed+a.EstimatedCost > a.Budget {
+			return Favor{}, fmt.Errorf("Account %v has %v left of its budget of %v, and a favor is expected to cost %v.", name, FormatCents(a.Budget-committed), FormatCents(a.Budget), FormatCents(a.EstimatedCost))
+		}
+	}
+	var err error
+	if rf.Street == "" && a.DefaultAddress.Street != "" {
+		if rf, err = rf.FromAddress(a.DefaultAddress); err != nil {
+			return Favor{}, err
+		}
+	}
+
+	var f Favor
+	err = p.Do(name, func(c *Client) (err error) {
+		f, err = c.PlaceFavor(rf)
+		return err
+	})
+	if err != nil {
+		return Favor{}, err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if amounts, err := f.Receipt.Amounts(); err == nil && amounts.Total() > 0 {
+		pa.spent += amounts.Total()
+	} else if a.Budget > 0 {
+		id := f.ID
+		if id == "" {
+			// It still needs to count, even if its receipt can never be
+			// matched up with it.
+			pa.unknown++
+			id = fmt.Sprintf("unknown-%d", pa.unknown)
+		}
+		pa.pending[id] = a.EstimatedCost
+	}
+	return f, nil
+}
@@ -0,0 +1,132 @@
+package favor
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolGetAllFavors(t *testing.T) {
+	austin := setupMockClient(t, respondWith(`{"count": 1, "favors": [{"id": "1"}]}`))
+	dallas := setupMockClient(t, respondWith(`{"count": 2, "favors": [{"id": "2"}, {"id": "3"}]}`))
+
+	p := NewPool()
+	assert.Nil(t, p.Add(Account{Name: "dallas", Client: dallas}))
+	assert.Nil(t, p.Add(Account{Name: "austin", Client: austin}))
+	assert.NotNil(t, p.Add(Account{Name: "austin", Client: austin}), "Duplicate accounts should be rejected")
+	assert.Equal(t, []string{"austin", "dallas"}, p.Names())
+
+	favors, err := p.GetAllFavors()
+	assert.Nil(t, err)
+	expected := []AccountFavor{
+		{Account: "austin", Favor: Favor{ID: "1"}},
+		{Account: "dallas", Favor: Favor{ID: "2"}},
+		{Account: "dallas", Favor: Favor{ID: "3"}},
+	}
+	assert.Equal(t, expected, favors)
+
+	_, err = p.GetFavors("houston")
+	assert.NotNil(t, err)
+}
+
+func TestPoolRateLimit(t *testing.T) {
+	c := setupMockClient(t, respondWith(`{"favor": {"id": "1"}}`))
+
+	p := NewPool()
+	p.Add(Account{Name: "austin", Client: c, MinInterval: 20 * time.Millisecond})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := p.GetFavor("austin", "1")
+		assert.Nil(t, err)
+	}
+	assert.True(t, time.Since(start) >= 40*time.Millisecond, "Requests should have been spaced out by the rate limit")
+}
+
+func TestPoolBudget(t *testing.T) {
+	c := setupMockClient(t, respondWith(`{"favor": {"id": "1", "receipt": {"price": "10.00", "tip": "2.50"}}}`))
+
+	p := NewPool()
+	p.Add(Account{
+		Name:           "austin",
+		Client:         c,
+		Budget:         1000,
+		EstimatedCost:  800,
+		DefaultAddress: Address{Lat: "30.2", Lng: "-97.7", Street: "42 Wallaby Way", Zipcode: "2000"},
+	})
+
+	_, err := p.PlaceFavor("austin", RequestFavor{Title: "Tacos", MarketID: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1250), p.Spent("austin"))
+
+	_, err = p.PlaceFavor("austin", RequestFavor{Title: "More tacos", MarketID: 1})
+	assert.NotNil(t, err, "Accounts over budget shouldn't be able to place favors")
+
+	p.ResetSpent("austin")
+	assert.Equal(t, int64(0), p.Spent("austin"))
+}
+
+func TestPoolBudgetNeedsEstimate(t *testing.T) {
+	c := setupMockClient(t, respondWith(`{}`))
+	p := NewPool()
+	assert.NotNil(t, p.Add(Account{Name: "austin", Client: c, Budget: 1000}))
+}
+
+func TestPoolBudgetCountsPendingFavors(t *testing.T) {
+	var lock sync.Mutex
+	placed := 0
+	receipts := map[string]string{}
+	c := setupMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if r.Method == "POST" {
+			placed++
+			fmt.Fprintf(w, `{"favor": {"id": "%d", "stage": "pending"}}`, placed)
+			return
+		}
+		id := strings.TrimPrefix(r.URL.Path, "/api/v5/favors/")
+		fmt.Fprintf(w, `{"favor": {"id": %q, "stage": "delivered", "receipt": %v}}`, id, receipts[id])
+	}))
+
+	p := NewPool()
+	assert.Nil(t, p.Add(Account{Name: "austin", Client: c, Budget: 2000, EstimatedCost: 600}))
+	rf := RequestFavor{Title: "Tacos", MarketID: 1}
+
+	// placed all at once, only as many as the estimate says fit get through
+	var wg sync.WaitGroup
+	var successes int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.PlaceFavor("austin", rf); err == nil {
+				atomic.AddInt32(&successes, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(3), successes)
+	assert.Equal(t, 3, placed)
+	assert.Equal(t, int64(0), p.Spent("austin"))
+	assert.Equal(t, int64(1800), p.Pending("austin"))
+
+	// once a receipt shows up, it counts instead of the estimate
+	lock.Lock()
+	receipts["1"] = `{"price": "1.00"}`
+	lock.Unlock()
+	_, err := p.GetFavor("austin", "1")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), p.Spent("austin"))
+	assert.Equal(t, int64(1200), p.Pending("austin"))
+
+	_, err = p.PlaceFavor("austin", rf)
+	assert.Nil(t, err, "The cheap receipt should have left room for another favor")
+	_, err = p.PlaceFavor("austin", rf)
+	assert.NotNil(t, err)
+}
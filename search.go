@@ -0,0 +1,223 @@
+package favor
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// How much of a search result's score comes from how well it matched, with
+// the rest coming from how close it is. searchDistanceScale is the distance,
+// in meters, at which a merchant's proximity score has dropped to about a third.
+const (
+	searchMatchWeight   = 0.7
+	searchDistanceScale = 3000.0
+	searchMinimumMatch  = 0.5
+)
+
+// SearchResult is a merchant that matched a search, along with how well it
+// matched. MatchScore and Score range from zero to one, and Distance is in
+// meters, or -1 if the merchant's coordinates are unusable.
+type SearchResult struct {
+	Merchant   Merchant
+	MatchScore float64
+	Distance   float64
+	Score      float64
+}
+
+// accentFolds maps accented characters to their unaccented equivalents, so that
+// "jalapeño" and "jalapeno" are treated the same. It's not exhaustive, but it
+// covers what shows up on menus around here.
+var accentFolds = map[rune]rune{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a',
+	'ç': 'c',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i',
+	'ñ': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u',
+	'ý': 'y', 'ÿ': 'y',
+	'ß': 's',
+}
+
+// searchTokens splits text up into lowercase, accent free words. Apostrophes
+// are dropped rather than treated as spaces, so "Torchy's" becomes "torchys".
+func searchTokens(text string) []string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if folded, ok := accentFolds[r]; ok {
+			r = folded
+		}
+		switch {
+		case r == '\'' || r == '’':
+			continue
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Fields(b.String())
+}
+
+// editDistance returns the optimal string alignment distance between two
+// words, which is the Levenshtein distance plus transpositions, since swapping
+// two letters is the most common typo there is.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	d := make([][]int, len(ar)+1)
+	for i := range d {
+		d[i] = make([]int, len(br)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, minInt(d[i][j-1]+1, d[i-1][j-1]+cost))
+			if i > 1 && j > 1 && ar[i-1] == br[j-2] && ar[i-2] == br[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ar)][len(br)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// allowedTypos is how many edits a query word can be off by and still match,
+// which grows with the length of the word.
+func allowedTypos(word string) int {
+	switch n := len([]rune(word)); {
+	case n <= 3:
+		return 0
+	case n <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// tokenScore rates how well a single query word matches a single candidate word.
+func tokenScore(query, candidate string) float64 {
+	switch {
+	case query == candidate:
+		return 1
+	case strings.HasPrefix(candidate, query) && len([]rune(query)) >= 3:
+		return 0.9
+	}
+
+	distance := editDistance(query, candidate)
+	// plurals and the like: "taco" should match "tacos" just as well as "tacos" does.
+	// A letter or two is the start of too many words to count, though.
+	queryLength, candidateLength := len([]rune(query)), len([]rune(candidate))
+	if queryLength >= 3 && candidateLength > queryLength {
+		if prefixDistance := editDistance(query, string([]rune(candidate)[:queryLength])); prefixDistance < distance {
+			distance = prefixDistance
+		}
+	}
+	if distance > allowedTypos(query) {
+		return 0
+	}
+	// Nothing that needed fixing scores as well as an actual prefix.
+	return math.Min(0.8, 0.8-0.15*float64(distance-1))
+}
+
+// matchScore rates how well a query matches some text, from zero to one. Every
+// word in the query is matched against its best counterpart in the text, and
+// the scores are averaged.
+func matchScore(query string, text string) float64 {
+	queryTokens := searchTokens(query)
+	textTokens := searchTokens(text)
+	if len(queryTokens) == 0 || len(textTokens) == 0 {
+		return 0
+	}
+
+	var total float64
+	for _, q := range queryTokens {
+		best := 0.0
+		for _, t := range textTokens {
+			best = math.Max(best, tokenScore(q, t))
+		}
+		total += best
+	}
+
+	// All of the words in the text smooshed together catch things like
+	// "bigtex" for "Big Tex".
+	joined := strings.Join(textTokens, "")
+	whole := tokenScore(strings.Join(queryTokens, ""), joined)
+
+	return math.Max(total/float64(len(queryTokens)), whole)
+}
+
+// Search matches the merchants against a query using their name and cuisine,
+// and ranks them by a blend of how well they matched and how close they are
+// to near. Merchants that don't match well enough are left out.
+func (m Merchants) Search(query string, near LatLng) []SearchResult {
+	results := []SearchResult{}
+	for _, merchant := range m {
+		score := math.Max(matchScore(query, merchant.Name), matchScore(query, merchant.Cuisine))
+		if score < searchMinimumMatch {
+			continue
+		}
+
+		result := SearchResult{Merchant: merchant, MatchScore: score, Distance: -1}
+		proximity := 0.0
+		if d, err := merchant.DistanceFrom(near); err == nil {
+			result.Distance = d
+			proximity = math.Exp(-d / searchDistanceScale)
+		}
+		result.Score = searchMatchWeight*score + (1-searchMatchWeight)*proximity
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// SearchMerchants finds merchants near a point whose name or cuisine matches
+// the query. Matching is done locally, and tolerates typos, accents, and
+// missing apostrophes, so "torchys" and "tacso" both find "Torchy's Tacos".
+func (c Client) SearchMerchants(ctx context.Context, query string, near LatLng) ([]SearchResult, error) {
+	if err := near.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type merchantsResult struct {
+		merchants Merchants
+		err       error
+	}
+	done := make(chan merchantsResult, 1)
+	go func() {
+		merchants, err := c.GetMerchants(near.Lat, near.Lng)
+		done <- merchantsResult{merchants, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		if r.err != nil {
+			return nil, r.err
+		}
+		return r.merchants.Search(query, near), nil
+	}
+}
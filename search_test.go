@@ -0,0 +1,75 @@
+package favor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTokens(t *testing.T) {
+	assert.Equal(t, []string{"torchys", "tacos"}, searchTokens("Torchy's Tacos!"))
+	assert.Equal(t, []string{"jalapeno", "cafe"}, searchTokens("Jalapeño  Café"))
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("tacos", "tacos"))
+	assert.Equal(t, 1, editDistance("tacso", "tacos"))
+	assert.Equal(t, 1, editDistance("taco", "tacos"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
+
+func TestMatchScore(t *testing.T) {
+	assert.Equal(t, 1.0, matchScore("torchys", "Torchy's Tacos"))
+	assert.True(t, matchScore("tacos", "Torchy's Tacos") > matchScore("tacso", "Torchy's Tacos"))
+	assert.True(t, matchScore("tacso", "Torchy's Tacos") >= searchMinimumMatch)
+	assert.True(t, matchScore("jalapeno", "Jalapeño Café") == 1)
+	assert.True(t, matchScore("bigtex", "Big Tex Corny Dogs") > searchMinimumMatch)
+	assert.True(t, matchScore("sushi", "Torchy's Tacos") < searchMinimumMatch)
+}
+
+func TestTokenScore(t *testing.T) {
+	assert.Equal(t, 0.0, tokenScore("t", "tacos"))
+	assert.Equal(t, 0.0, tokenScore("ta", "tacos"))
+	assert.Equal(t, 0.9, tokenScore("tac", "tacos"))
+	assert.True(t, tokenScore("tacso", "tacos") < tokenScore("tac", "tacos"), "typos shouldn't beat a real prefix")
+	assert.True(t, tokenScore("tzcos", "tacoss") < 0.9)
+}
+
+func TestSearchMerchants(t *testing.T) {
+	dummyMerchantsResponse := `
+	{
+		"merchants": [
+			{"id": "1", "name": "Torchy's Tacos", "lat": "30.3500", "lng": "-97.7500"},
+			{"id": "2", "name": "Torchy's Tacos", "lat": "30.2672", "lng": "-97.7431"},
+			{"id": "3", "name": "Veracruz All Natural", "lat": "30.2672", "lng": "-97.7431", "cuisine": "Tacos"},
+			{"id": "4", "name": "Uchi", "lat": "30.2672", "lng": "-97.7431", "cuisine": "Sushi"}
+		]
+	}`
+
+	s := setupMockClient(t, respondWith(dummyMerchantsResponse))
+
+	results, err := s.SearchMerchants(context.Background(), "torchys", austin)
+	assert.Nil(t, err)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "2", results[0].Merchant.ID, "The closer Torchy's should rank first")
+		assert.Equal(t, "1", results[1].Merchant.ID)
+	}
+
+	results, err = s.SearchMerchants(context.Background(), "tacso", austin)
+	assert.Nil(t, err)
+	assert.Len(t, results, 3, "Cuisine should be searched too")
+
+	results, err = s.SearchMerchants(context.Background(), "t", austin)
+	assert.Nil(t, err)
+	assert.Empty(t, results, "A single letter is the start of too many words to match anything")
+
+	_, err = s.SearchMerchants(context.Background(), "tacos", LatLng{Lat: 100})
+	assert.NotNil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = s.SearchMerchants(ctx, "tacos", austin)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// listFavors is GET /v1/favors
+func (s *Server) listFavors(r request) (int, interface{}, error) {
+	favors, err := r.client.GetFavors()
+	if err != nil {
+		return 0, nil, upstream(err)
+	}
+	out := []Favor{}
+	for _, f := range favors {
+		converted, err := favorFrom(f)
+		if err != nil {
+			return 0, nil, upstream(err)
+		}
+		out = append(out, converted)
+	}
+	return http.StatusOK, map[string]interface{}{"favors": out}, nil
+}
+
+// getFavor is GET /v1/favors/{id}
+func (s *Server) getFavor(r request) (int, interface{}, error) {
+	f, err := r.client.GetFavor(r.params["id"])
+	if err != nil {
+		return 0, nil, upstream(err)
+	}
+	if f.ID == "" {
+		return 0, nil, notFound("there's no favor %v", r.params["id"])
+	}
+	out, err := favorFrom(f)
+	if err != nil {
+		return 0, nil, upstream(err)
+	}
+	return http.StatusOK, out, nil
+}
+
+// placeFavor is POST /v1/favors
+func (s *Server) placeFavor(r request) (int, interface{}, error) {
+	fr := FavorRequest{}
+	if err := decode(r, &fr); err != nil {
+		return 0, nil, err
+	}
+	rf, err := fr.upstream()
+	if err != nil {
+		return 0, nil, badRequest("%v", err)
+	}
+	f, err := r.client.PlaceFavor(rf)
+	if err != nil {
+		return 0, nil, upstream(err)
+	}
+	out, err := favorFrom(f)
+	if err != nil {
+		return 0, nil, upstream(err)
+	}
+	return http.StatusCreated, out, nil
+}
+
+// listMerchants is GET /v1/merchants?lat=&lng=[&q=][&open=true]
+func (s *Server) listMerchants(r request) (int, interface{}, error) {
+	query := r.URL.Query()
+	near, err := favor.ParseLatLng(query.Get("lat"), query.Get("lng"))
+	if err != nil {
+		return 0, nil, badRequest("lat and lng are required: %v", err)
+	}
+	now := s.Now()
+
+	var merchants favor.Merchants
+	if q := strings.TrimSpace(query.Get("q")); q != "" {
+		results, err := r.client.SearchMerchants(r.Context(), q, near)
+		if err != nil {
+			return 0, nil, upstream(err)
+		}
+		for _, result := range results {
+			merchants = append(merchants, result.Merchant)
+		}
+	} else {
+		found, err := r.client.GetMerchants(near.Lat, near.Lng)
+		if err != nil {
+			return 0, nil, upstream(err)
+		}
+		merchants = favor.Merchants(found).SortByDistance(near)
+	}
+	if query.Get("open") == "true" {
+		merchants = merchants.Filter(favor.OpenAt(now))
+	}
+
+	out := []Merchant{}
+	for _, m := range merchants {
+		out = append(out, merchantFrom(m, now, &near))
+	}
+	return http.StatusOK, map[string]interface{}{"merchants": out}, nil
+}
+
+// getMerchant is GET /v1/merchants/{id}
+func (s *Server) getMerchant(r request) (int, interface{}, error) {
+	m, err := r.client.GetMerchant(r.params["id"])
+	if err != nil {
+		return 0, nil, upstream(err)
+	}
+	if m.ID == "" {
+		return 0, nil, notFound("there's no merchant %v", r.params["id"])
+	}
+	return http.StatusOK, merchantFrom(m, s.Now(), nil), nil
+}
+
+// getMenu is GET /v1/merchants/{id}/menu
+func (s *Server) getMenu(r request) (int, interface{}, error) {
+	m, err := r.client.GetMenu(r.params["id"])
+	if err != nil {
+		return 0, nil, upstream(err)
+	}
+	out, err := menuFrom(r.params["id"], m)
+	if err != nil {
+		return 0, nil, upstream(err)
+	}
+	return http.StatusOK, out, nil
+}
+
+// listMarkets is GET /v1/markets
+func (s *Server) listMarkets(r request) (int, interface{}, error) {
+	markets, err := r.client.GetMarkets()
+	if err != nil {
+		return 0, nil, upstream(err)
+	}
+	out := []Market{}
+	for _, m := range markets {
+		out = append(out, marketFrom(m))
+	}
+	return http.StatusOK, map[string]interface{}{"markets": out}, nil
+}
+
+// getMe is GET /v1/me
+func (s *Server) getMe(r request) (int, interface{}, error) {
+	u, err := r.client.GetMe()
+	if err != nil {
+		return 0, nil, upstream(err)
+	}
+	return http.StatusOK, userFrom(u), nil
+}
+
+// updateMe is PATCH /v1/me. UpdateMe only sends the fields that are set, so
+// the ones the caller left out stay as they are.
+func (s *Server) updateMe(r request) (int, interface{}, error) {
+	uu := UserUpdate{}
+	if err := decode(r, &uu); err != nil {
+		return 0, nil, err
+	}
+	updated, err := r.client.UpdateMe(uu.apply(favor.User{}))
+	if err != nil {
+		return 0, nil, upstream(err)
+	}
+	return http.StatusOK, userFrom(updated), nil
+}
+
+// listAddresses is GET /v1/addresses
+func (s *Server) listAddresses(r request) (int, interface{}, error) {
+	addresses, err := r.client.ListAddresses()
+	if err != nil {
+		return 0, nil, upstream(err)
+	}
+	out := []Address{}
+	for _, a := range addresses {
+		out = append(out, addressFrom(a))
+	}
+	return http.StatusOK, map[string]interface{}{"addresses": out}, nil
+}
+
+// validateAddress makes sure an address from a caller has what the Favor API
+// needs to deliver to it.
+func validateAddress(a Address) error {
+	missing := []string{}
+	if strings.TrimSpace(a.Street) == "" {
+		missing = append(missing, "street")
+	}
+	if strings.TrimSpace(a.Zipcode) == "" {
+		missing = append(missing, "zipcode")
+	}
+	if a.Location == nil {
+		missing = append(missing, "location")
+	}
+	if len(missing) > 0 {
+		return badRequest("the address is missing required fields: %v", strings.Join(missing, ", "))
+	}
+	if err := (favor.LatLng{Lat: a.Location.Lat, Lng: a.Location.Lng}).Validate(); err != nil {
+		return badRequest("%v", err)
+	}
+	return nil
+}
+
+// createAddress is POST /v1/addresses
+func (s *Server) createAddress(r request) (int, interface{}, error) {
+	a := Address{}
+	if err := decode(r, &a); err != nil {
+		return 0, nil, err
+	}
+	if err := validateAddress(a); err != nil {
+		return 0, nil, err
+	}
+	a.ID = ""
+	created, err := r.client.CreateAddress(a.upstream())
+	if err != nil {
+		return 0, nil, upstream(err)
+	}
+	return http.StatusCreated, addressFrom(created), nil
+}
+
+// updateAddress is PUT /v1/addresses/{id}
+func (s *Server) updateAddress(r request) (int, interface{}, error) {
+	a := Address{}
+	if err := decode(r, &a); err != nil {
+		return 0, nil, err
+	}
+	if err := validateAddress(a); err != nil {
+		return 0, nil, err
+	}
+	if a.ID != "" && a.ID != r.params["id"] {
+		return 0, nil, badRequest("the body is for address %v, but the path is for address %v", a.ID, r.params["id"])
+	}
+	a.ID = r.params["id"]
+	updated, err := r.client.UpdateAddress(a.upstream())
+	if err != nil {
+		return 0, nil, upstream(err)
+	}
+	return http.StatusOK, addressFrom(updated), nil
+}
+
+// deleteAddress is DELETE /v1/addresses/{id}
+func (s *Server) deleteAddress(r request) (int, interface{}, error) {
+	if err := r.client.DeleteAddress(r.params["id"]); err != nil {
+		return 0, nil, upstream(err)
+	}
+	return http.StatusNoContent, nil, nil
+}
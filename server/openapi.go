@@ -0,0 +1,349 @@
+package server
+
+// OpenAPI describes the gateway, and is served at /openapi.json. If you add a
+// route, add it here too; the tests will notice if you don't.
+const OpenAPI = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "Favor gateway",
+    "version": "1.0.0",
+    "description": "A JSON REST API in front of the Favor API. Money is in cents, times are RFC 3339, and errors always look like {\"error\": {\"code\": \"...\", \"message\": \"...\"}}. Failures talking to Favor itself are reported as 502, including Favor turning down the token behind an API key, which has the code upstream_unauthorized. A 401 always means the API key itself is wrong."
+  },
+  "servers": [{"url": "/"}],
+  "security": [{"bearer": []}, {"apiKey": []}],
+  "paths": {
+    "/v1/favors": {
+      "get": {
+        "operationId": "listFavors",
+        "summary": "List the caller's favors",
+        "responses": {
+          "200": {"description": "The caller's favors", "content": {"application/json": {"schema": {"type": "object", "properties": {"favors": {"type": "array", "items": {"$ref": "#/components/schemas/Favor"}}}}}}},
+          "401": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "post": {
+        "operationId": "placeFavor",
+        "summary": "Place a favor",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/FavorRequest"}}}},
+        "responses": {
+          "201": {"description": "The favor that was placed", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Favor"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "401": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/favors/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/ID"}],
+      "get": {
+        "operationId": "getFavor",
+        "summary": "Get a single favor",
+        "responses": {
+          "200": {"description": "The favor", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Favor"}}}},
+          "401": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/merchants": {
+      "get": {
+        "operationId": "listMerchants",
+        "summary": "List merchants near a point, closest first, or best match first when searching",
+        "parameters": [
+          {"name": "lat", "in": "query", "required": true, "schema": {"type": "number"}},
+          {"name": "lng", "in": "query", "required": true, "schema": {"type": "number"}},
+          {"name": "q", "in": "query", "description": "Search merchant names, forgiving typos", "schema": {"type": "string"}},
+          {"name": "open", "in": "query", "description": "Only merchants that are open right now", "schema": {"type": "boolean"}}
+        ],
+        "responses": {
+          "200": {"description": "Nearby merchants", "content": {"application/json": {"schema": {"type": "object", "properties": {"merchants": {"type": "array", "items": {"$ref": "#/components/schemas/Merchant"}}}}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "401": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/merchants/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/ID"}],
+      "get": {
+        "operationId": "getMerchant",
+        "summary": "Get a single merchant",
+        "responses": {
+          "200": {"description": "The merchant", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Merchant"}}}},
+          "401": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/merchants/{id}/menu": {
+      "parameters": [{"$ref": "#/components/parameters/ID"}],
+      "get": {
+        "operationId": "getMenu",
+        "summary": "Get a merchant's menu",
+        "responses": {
+          "200": {"description": "The menu, which is empty for merchants without an expanded menu", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Menu"}}}},
+          "401": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/markets": {
+      "get": {
+        "operationId": "listMarkets",
+        "summary": "List the markets Favor operates in",
+        "responses": {
+          "200": {"description": "Every market", "content": {"application/json": {"schema": {"type": "object", "properties": {"markets": {"type": "array", "items": {"$ref": "#/components/schemas/Market"}}}}}}},
+          "401": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/me": {
+      "get": {
+        "operationId": "getMe",
+        "summary": "Get the profile of the user behind the caller's token",
+        "responses": {
+          "200": {"description": "The user", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}},
+          "401": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "patch": {
+        "operationId": "updateMe",
+        "summary": "Change some of the profile, leaving everything left out as it was",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/UserUpdate"}}}},
+        "responses": {
+          "200": {"description": "The updated user", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "401": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/addresses": {
+      "get": {
+        "operationId": "listAddresses",
+        "summary": "List saved addresses",
+        "responses": {
+          "200": {"description": "Saved addresses", "content": {"application/json": {"schema": {"type": "object", "properties": {"addresses": {"type": "array", "items": {"$ref": "#/components/schemas/Address"}}}}}}},
+          "401": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "post": {
+        "operationId": "createAddress",
+        "summary": "Save an address",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Address"}}}},
+        "responses": {
+          "201": {"description": "The saved address", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Address"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "401": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/addresses/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/ID"}],
+      "put": {
+        "operationId": "updateAddress",
+        "summary": "Replace a saved address",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Address"}}}},
+        "responses": {
+          "200": {"description": "The updated address", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Address"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "401": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "delete": {
+        "operationId": "deleteAddress",
+        "summary": "Delete a saved address",
+        "responses": {
+          "204": {"description": "The address is gone"},
+          "401": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    }
+  },
+  "components": {
+    "securitySchemes": {
+      "bearer": {"type": "http", "scheme": "bearer", "description": "The caller's API key"},
+      "apiKey": {"type": "apiKey", "in": "header", "name": "X-API-Key"}
+    },
+    "parameters": {
+      "ID": {"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}
+    },
+    "responses": {
+      "Error": {"description": "Something went wrong", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+    },
+    "schemas": {
+      "Error": {
+        "type": "object",
+        "properties": {"error": {"type": "object", "properties": {"code": {"type": "string"}, "message": {"type": "string"}}}}
+      },
+      "Location": {
+        "type": "object",
+        "required": ["lat", "lng"],
+        "properties": {"lat": {"type": "number"}, "lng": {"type": "number"}}
+      },
+      "Person": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "forename": {"type": "string"},
+          "surname": {"type": "string"},
+          "phone": {"type": "string"},
+          "email": {"type": "string"}
+        }
+      },
+      "Receipt": {
+        "type": "object",
+        "properties": {
+          "paid_cents": {"type": "integer"},
+          "price_cents": {"type": "integer"},
+          "tip_cents": {"type": "integer"},
+          "delivery_charge_cents": {"type": "integer"},
+          "card_fee_cents": {"type": "integer"},
+          "total_cents": {"type": "integer", "description": "Price plus tip, delivery charge and card fee"}
+        }
+      },
+      "Address": {
+        "type": "object",
+        "required": ["street", "zipcode", "location"],
+        "properties": {
+          "id": {"type": "string", "readOnly": true},
+          "street": {"type": "string"},
+          "zipcode": {"type": "string"},
+          "apartment": {"type": "string"},
+          "notes": {"type": "string"},
+          "location": {"$ref": "#/components/schemas/Location"}
+        }
+      },
+      "Favor": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "title": {"type": "string"},
+          "items": {"type": "array", "items": {"type": "string"}},
+          "stage": {"type": "string"},
+          "status": {"type": "string"},
+          "finished": {"type": "boolean"},
+          "merchant_id": {"type": "string"},
+          "created_at": {"type": "string", "format": "date-time"},
+          "customer": {"$ref": "#/components/schemas/Person"},
+          "runner": {"$ref": "#/components/schemas/Person"},
+          "delivery_address": {"$ref": "#/components/schemas/Address"},
+          "receipt": {"$ref": "#/components/schemas/Receipt"}
+        }
+      },
+      "FavorRequest": {
+        "type": "object",
+        "required": ["title", "location", "street", "zipcode"],
+        "description": "At least one of wants and items is required.",
+        "properties": {
+          "title": {"type": "string"},
+          "wants": {"type": "string"},
+          "items": {"type": "array", "items": {"type": "string"}},
+          "location": {"$ref": "#/components/schemas/Location"},
+          "street": {"type": "string"},
+          "zipcode": {"type": "string"},
+          "apartment": {"type": "string"},
+          "notes": {"type": "string"},
+          "merchant_id": {"type": "string"},
+          "market_id": {"type": "string", "description": "Worked out from the location if left out"}
+        }
+      },
+      "Merchant": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "name": {"type": "string"},
+          "phone": {"type": "string"},
+          "address": {"type": "string"},
+          "city": {"type": "string"},
+          "state": {"type": "string"},
+          "zipcode": {"type": "string"},
+          "cuisine": {"type": "string"},
+          "market_id": {"type": "string"},
+          "franchise_id": {"type": "string"},
+          "location": {"$ref": "#/components/schemas/Location"},
+          "distance_meters": {"type": "number"},
+          "expanded_menu": {"type": "boolean"},
+          "car_only": {"type": "boolean"},
+          "open_now": {"type": "boolean", "description": "Left out when the merchant has no hours listed"},
+          "hours": {"type": "array", "items": {"type": "string"}}
+        }
+      },
+      "Menu": {
+        "type": "object",
+        "properties": {
+          "merchant_id": {"type": "string"},
+          "categories": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "id": {"type": "string"},
+                "name": {"type": "string"},
+                "meals": {
+                  "type": "array",
+                  "items": {
+                    "type": "object",
+                    "properties": {
+                      "id": {"type": "string"},
+                      "name": {"type": "string"},
+                      "description": {"type": "string"},
+                      "price_cents": {"type": "integer"},
+                      "options": {
+                        "type": "array",
+                        "items": {
+                          "type": "object",
+                          "properties": {"id": {"type": "string"}, "name": {"type": "string"}, "price_cents": {"type": "integer"}}
+                        }
+                      }
+                    }
+                  }
+                }
+              }
+            }
+          }
+        }
+      },
+      "Market": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "name": {"type": "string"},
+          "city": {"type": "string"},
+          "state": {"type": "string"},
+          "timezone": {"type": "string"},
+          "center": {"$ref": "#/components/schemas/Location"},
+          "radius_meters": {"type": "number"}
+        }
+      },
+      "User": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "forename": {"type": "string"},
+          "surname": {"type": "string"},
+          "phone": {"type": "string"},
+          "email": {"type": "string"}
+        }
+      },
+      "UserUpdate": {
+        "type": "object",
+        "properties": {
+          "forename": {"type": "string"},
+          "surname": {"type": "string"},
+          "email": {"type": "string"}
+        }
+      }
+    }
+  }
+}
+`
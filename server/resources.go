@@ -0,0 +1,376 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// The types in this file are what the gateway speaks. They're the upstream
+// types with the quirks sanded off: numbers are numbers, money is in cents,
+// booleans are booleans, times are RFC 3339, and coordinates come in pairs.
+
+// Location is a point on the map.
+type Location struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// locationFrom converts the API's pair of strings into a Location, or nil if
+// they don't make a valid point.
+func locationFrom(lat, lng string) *Location {
+	p, err := favor.ParseLatLng(lat, lng)
+	if err != nil {
+		return nil
+	}
+	return &Location{Lat: p.Lat, Lng: p.Lng}
+}
+
+// Person is a customer or a runner.
+type Person struct {
+	ID       string `json:"id"`
+	Forename string `json:"forename,omitempty"`
+	Surname  string `json:"surname,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
+
+func personFrom(u favor.User) *Person {
+	if u.ID == "" && u.Forename == "" && u.Surname == "" {
+		return nil
+	}
+	return &Person{ID: u.ID, Forename: u.Forename, Surname: u.Surname, Phone: u.Phone, Email: u.Email}
+}
+
+// Receipt is what a favor cost, in cents.
+type Receipt struct {
+	PaidCents           int64 `json:"paid_cents"`
+	PriceCents          int64 `json:"price_cents"`
+	TipCents            int64 `json:"tip_cents"`
+	DeliveryChargeCents int64 `json:"delivery_charge_cents"`
+	CardFeeCents        int64 `json:"card_fee_cents"`
+	TotalCents          int64 `json:"total_cents"`
+}
+
+func receiptFrom(r favor.Receipt) (*Receipt, error) {
+	if r == (favor.Receipt{}) {
+		return nil, nil
+	}
+	ra, err := r.Amounts()
+	if err != nil {
+		return nil, err
+	}
+	return &Receipt{
+		PaidCents:           ra.Paid,
+		PriceCents:          ra.Price,
+		TipCents:            ra.Tip,
+		DeliveryChargeCents: ra.DeliveryCharge,
+		CardFeeCents:        ra.CcFeeAmount,
+		TotalCents:          ra.Total(),
+	}, nil
+}
+
+// Address is a saved delivery address.
+type Address struct {
+	ID        string    `json:"id,omitempty"`
+	Street    string    `json:"street"`
+	Zipcode   string    `json:"zipcode"`
+	Apartment string    `json:"apartment,omitempty"`
+	Notes     string    `json:"notes,omitempty"`
+	Location  *Location `json:"location,omitempty"`
+}
+
+func addressFrom(a favor.Address) Address {
+	return Address{
+		ID:        a.ID,
+		Street:    a.Street,
+		Zipcode:   a.Zipcode,
+		Apartment: a.Apartment,
+		Notes:     a.Notes,
+		Location:  locationFrom(a.Lat, a.Lng),
+	}
+}
+
+func (a Address) upstream() favor.Address {
+	fa := favor.Address{ID: a.ID, Street: a.Street, Zipcode: a.Zipcode, Apartment: a.Apartment, Notes: a.Notes}
+	if a.Location != nil {
+		fa.Lat = strconv.FormatFloat(a.Location.Lat, 'f', -1, 64)
+		fa.Lng = strconv.FormatFloat(a.Location.Lng, 'f', -1, 64)
+	}
+	return fa
+}
+
+// Favor is an errand someone asked for.
+type Favor struct {
+	ID              string     `json:"id"`
+	Title           string     `json:"title"`
+	Items           []string   `json:"items"`
+	Stage           string     `json:"stage"`
+	Status          string     `json:"status,omitempty"`
+	Finished        bool       `json:"finished"`
+	MerchantID      string     `json:"merchant_id,omitempty"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+	Customer        *Person    `json:"customer,omitempty"`
+	Runner          *Person    `json:"runner,omitempty"`
+	DeliveryAddress *Address   `json:"delivery_address,omitempty"`
+	Receipt         *Receipt   `json:"receipt,omitempty"`
+}
+
+func favorFrom(f favor.Favor) (Favor, error) {
+	receipt, err := receiptFrom(f.Receipt)
+	if err != nil {
+		return Favor{}, err
+	}
+	out := Favor{
+		ID:         f.ID,
+		Title:      f.Title,
+		Items:      f.Items,
+		Stage:      f.Stage,
+		Status:     f.LastStatus,
+		Finished:   f.IsFinished(),
+		MerchantID: f.MerchantID,
+		Customer:   personFrom(f.Customer),
+		Runner:     personFrom(f.Runner),
+		Receipt:    receipt,
+	}
+	if out.Items == nil {
+		out.Items = []string{}
+	}
+	if out.MerchantID == "" {
+		out.MerchantID = f.Merchant.ID
+	}
+	if f.CreatedAt > 0 {
+		created := time.Unix(int64(f.CreatedAt), 0).UTC()
+		out.CreatedAt = &created
+	}
+	if f.DeliveryAddress != (favor.Address{}) {
+		a := addressFrom(f.DeliveryAddress)
+		out.DeliveryAddress = &a
+	}
+	return out, nil
+}
+
+// FavorRequest is what callers send to place a favor. Wants can be given as
+// free text, as a list of Items, or both.
+type FavorRequest struct {
+	Title      string    `json:"title"`
+	Wants      string    `json:"wants,omitempty"`
+	Items      []string  `json:"items,omitempty"`
+	Location   *Location `json:"location"`
+	Street     string    `json:"street"`
+	Zipcode    string    `json:"zipcode"`
+	Apartment  string    `json:"apartment,omitempty"`
+	Notes      string    `json:"notes,omitempty"`
+	MerchantID string    `json:"merchant_id,omitempty"`
+	MarketID   string    `json:"market_id,omitempty"`
+}
+
+// upstream converts the request into what the Favor API expects, and checks
+// it for anything the API would be unhappy about.
+func (fr FavorRequest) upstream() (favor.RequestFavor, error) {
+	wants := append([]string{}, fr.Items...)
+	if fr.Wants != "" {
+		wants = append(wants, fr.Wants)
+	}
+	rf := favor.RequestFavor{
+		Title:   fr.Title,
+		Wants:   strings.Join(wants, "\n"),
+		Street:  fr.Street,
+		Zipcode: fr.Zipcode,
+		Apt:     fr.Apartment,
+		Notes:   fr.Notes,
+	}
+	if fr.Location != nil {
+		rf.Lat, rf.Lng = fr.Location.Lat, fr.Location.Lng
+	}
+
+	var err error
+	if fr.MerchantID != "" {
+		if rf.MerchantID, err = strconv.Atoi(fr.MerchantID); err != nil {
+			return favor.RequestFavor{}, fmt.Errorf("merchant_id %q is not a valid ID", fr.MerchantID)
+		}
+	}
+	if fr.MarketID != "" {
+		if rf.MarketID, err = strconv.Atoi(fr.MarketID); err != nil {
+			return favor.RequestFavor{}, fmt.Errorf("market_id %q is not a valid ID", fr.MarketID)
+		}
+	}
+	if err := rf.Validate(nil); err != nil {
+		return favor.RequestFavor{}, err
+	}
+	return rf, nil
+}
+
+// Merchant is a place that sells things.
+type Merchant struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Phone          string    `json:"phone,omitempty"`
+	Address        string    `json:"address,omitempty"`
+	City           string    `json:"city,omitempty"`
+	State          string    `json:"state,omitempty"`
+	Zipcode        string    `json:"zipcode,omitempty"`
+	Cuisine        string    `json:"cuisine,omitempty"`
+	MarketID       string    `json:"market_id,omitempty"`
+	FranchiseID    string    `json:"franchise_id,omitempty"`
+	Location       *Location `json:"location,omitempty"`
+	DistanceMeters *float64  `json:"distance_meters,omitempty"`
+	ExpandedMenu   bool      `json:"expanded_menu"`
+	CarOnly        bool      `json:"car_only"`
+	OpenNow        *bool     `json:"open_now,omitempty"`
+	Hours          []string  `json:"hours"`
+}
+
+// merchantFrom converts a merchant. near is where the caller is, if they said,
+// and is used to work out how far away the merchant is.
+func merchantFrom(m favor.Merchant, now time.Time, near *favor.LatLng) Merchant {
+	out := Merchant{
+		ID:           m.ID,
+		Name:         m.Name,
+		Phone:        m.Phone,
+		Address:      m.Address,
+		City:         m.City,
+		State:        m.State,
+		Zipcode:      m.Zipcode,
+		Cuisine:      m.Cuisine,
+		MarketID:     m.MarketID,
+		FranchiseID:  m.FranchiseID,
+		Location:     locationFrom(m.Lat, m.Lng),
+		ExpandedMenu: m.HasExpandedMenu == "1",
+		CarOnly:      m.IsCarOnly == "1",
+		Hours:        []string{},
+	}
+	if len(m.Hours) > 0 {
+		open := m.IsOpenAt(now)
+		out.OpenNow = &open
+	}
+	for _, h := range m.Hours {
+		out.Hours = append(out.Hours, h.String())
+	}
+	if near != nil {
+		if d, err := m.DistanceFrom(*near); err == nil {
+			out.DistanceMeters = &d
+		}
+	}
+	return out
+}
+
+// MenuOption is a modifier for a meal.
+type MenuOption struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	PriceCents int64  `json:"price_cents"`
+}
+
+// Meal is something on a menu.
+type Meal struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	PriceCents  int64        `json:"price_cents"`
+	Options     []MenuOption `json:"options"`
+}
+
+// MenuCategory is a section of a menu.
+type MenuCategory struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Meals []Meal `json:"meals"`
+}
+
+// Menu is everything a merchant sells.
+type Menu struct {
+	MerchantID string         `json:"merchant_id"`
+	Categories []MenuCategory `json:"categories"`
+}
+
+// cents converts a price from a menu, which is a dollar string.
+func cents(price string) (int64, error) {
+	ra, err := favor.Receipt{Price: price}.Amounts()
+	return ra.Price, err
+}
+
+func menuFrom(merchantID string, m favor.Menu) (Menu, error) {
+	out := Menu{MerchantID: merchantID, Categories: []MenuCategory{}}
+	for _, c := range m.Categories {
+		category := MenuCategory{ID: c.ID, Name: c.Name, Meals: []Meal{}}
+		for _, meal := range c.Meals {
+			price, err := cents(meal.Price)
+			if err != nil {
+				return Menu{}, err
+			}
+			converted := Meal{ID: meal.ID, Name: meal.Name, Description: meal.Description, PriceCents: price, Options: []MenuOption{}}
+			for _, o := range meal.Options {
+				price, err := cents(o.Price)
+				if err != nil {
+					return Menu{}, err
+				}
+				converted.Options = append(converted.Options, MenuOption{ID: o.ID, Name: o.Name, PriceCents: price})
+			}
+			category.Meals = append(category.Meals, converted)
+		}
+		out.Categories = append(out.Categories, category)
+	}
+	return out, nil
+}
+
+// Market is a region Favor operates in.
+type Market struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	City         string    `json:"city,omitempty"`
+	State        string    `json:"state,omitempty"`
+	Timezone     string    `json:"timezone,omitempty"`
+	Center       *Location `json:"center,omitempty"`
+	RadiusMeters float64   `json:"radius_meters,omitempty"`
+}
+
+func marketFrom(m favor.Market) Market {
+	return Market{
+		ID:           m.ID,
+		Name:         m.Name,
+		City:         m.City,
+		State:        m.State,
+		Timezone:     m.Timezone,
+		Center:       locationFrom(m.Lat, m.Lng),
+		RadiusMeters: m.Radius,
+	}
+}
+
+// User is the person the caller's token belongs to.
+type User struct {
+	ID       string `json:"id"`
+	Forename string `json:"forename"`
+	Surname  string `json:"surname"`
+	Phone    string `json:"phone,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
+
+func userFrom(u favor.User) User {
+	return User{ID: u.ID, Forename: u.Forename, Surname: u.Surname, Phone: u.Phone, Email: u.Email}
+}
+
+// UserUpdate is a partial update to a User. Fields that are left out aren't
+// changed, and neither are ones set to an empty string, since the Favor API
+// has no way to clear them.
+type UserUpdate struct {
+	Forename *string `json:"forename,omitempty"`
+	Surname  *string `json:"surname,omitempty"`
+	Email    *string `json:"email,omitempty"`
+}
+
+func (uu UserUpdate) apply(u favor.User) favor.User {
+	if uu.Forename != nil {
+		u.Forename = *uu.Forename
+	}
+	if uu.Surname != nil {
+		u.Surname = *uu.Surname
+	}
+	if uu.Email != nil {
+		u.Email = *uu.Email
+	}
+	return u
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+func TestFavorRequestUpstream(t *testing.T) {
+	fr := FavorRequest{
+		Title:      "Torchy's Tacos",
+		Wants:      "extra salsa please",
+		Items:      []string{"2x Trailer Park"},
+		Location:   &Location{Lat: 30.2672, Lng: -97.7431},
+		Street:     "123 Fake St",
+		Zipcode:    "78701",
+		Apartment:  "4B",
+		MerchantID: "2",
+	}
+	rf, err := fr.upstream()
+	assert.Nil(t, err)
+	assert.Equal(t, "2x Trailer Park\nextra salsa please", rf.Wants)
+	assert.Equal(t, "4B", rf.Apt)
+	assert.Equal(t, 2, rf.MerchantID)
+	assert.Equal(t, 30.2672, rf.Lat)
+
+	fr.MerchantID = "torchys"
+	_, err = fr.upstream()
+	assert.NotNil(t, err, "Merchant IDs that aren't numbers should be refused")
+
+	fr.MerchantID, fr.Wants, fr.Items = "", "", nil
+	_, err = fr.upstream()
+	assert.NotNil(t, err, "Requests that don't want anything should be refused")
+}
+
+func TestMerchantFrom(t *testing.T) {
+	m := favor.Merchant{ID: "2", Name: "Torchy's Tacos", Lat: "30.2672", Lng: "-97.7431", IsCarOnly: "1"}
+	converted := merchantFrom(m, time.Now(), nil)
+	assert.True(t, converted.CarOnly)
+	assert.False(t, converted.ExpandedMenu)
+	assert.Nil(t, converted.OpenNow, "Merchants without hours shouldn't claim to be open or closed")
+	assert.Nil(t, converted.DistanceMeters)
+	assert.Equal(t, &Location{Lat: 30.2672, Lng: -97.7431}, converted.Location)
+	assert.Equal(t, []string{}, converted.Hours)
+
+	m.Lat = "north"
+	assert.Nil(t, merchantFrom(m, time.Now(), nil).Location)
+}
+
+func TestMenuFrom(t *testing.T) {
+	menu := favor.Menu{Categories: []favor.MenuCategory{{
+		ID:   "1",
+		Name: "Tacos",
+		Meals: []favor.Meal{{
+			ID:      "10",
+			Name:    "Trailer Park",
+			Price:   "4.75",
+			Options: []favor.MenuOption{{ID: "100", Name: "Get it trashy", Price: "0.50"}},
+		}},
+	}}}
+	converted, err := menuFrom("2", menu)
+	assert.Nil(t, err)
+	assert.Equal(t, "2", converted.MerchantID)
+	assert.Equal(t, int64(475), converted.Categories[0].Meals[0].PriceCents)
+	assert.Equal(t, int64(50), converted.Categories[0].Meals[0].Options[0].PriceCents)
+
+	menu.Categories[0].Meals[0].Price = "market price"
+	_, err = menuFrom("2", menu)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,374 @@
+// Package server is a JSON REST gateway in front of the Favor API, so that
+// nobody else has to learn about favorToken headers, form encoded POSTs and
+// numbers that are secretly strings.
+//
+// Callers authenticate with an API key, either as a bearer token or in the
+// X-API-Key header, and each key is mapped to the Favor token requests are
+// made with. The full API is described by the OpenAPI document served at
+// /openapi.json.
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// Caller is someone allowed to use the gateway, and the Favor token their
+// requests are made with.
+type Caller struct {
+	Name   string
+	Tokens favor.TokenSource
+}
+
+// KeyStore maps API keys to callers.
+type KeyStore interface {
+	Lookup(apiKey string) (Caller, bool)
+}
+
+// Keys is a KeyStore that lives in memory.
+type Keys map[string]Caller
+
+// Lookup finds the caller an API key belongs to. Every key is compared in
+// constant time, so that how long a lookup takes doesn't give away how close
+// a guess was.
+func (k Keys) Lookup(apiKey string) (Caller, bool) {
+	var found Caller
+	ok := false
+	for key, caller := range k {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+			found, ok = caller, true
+		}
+	}
+	return found, ok
+}
+
+// keyFile is what LoadKeys expects to find. Each caller either has their token
+// in the file directly, or in a token file held to the usual 0600 standard.
+type keyFile struct {
+	Callers []struct {
+		Name      string `json:"name"`
+		Key       string `json:"key"`
+		Token     string `json:"token,omitempty"`
+		TokenFile string `json:"token_file,omitempty"`
+	} `json:"callers"`
+}
+
+// LoadKeys reads API keys from a JSON file that looks like:
+//
+//	{"callers": [{"name": "billing", "key": "...", "token": "..."}]}
+func LoadKeys(path string) (Keys, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	kf := keyFile{}
+	if err := json.Unmarshal(contents, &kf); err != nil {
+		return nil, fmt.Errorf("the key file %v is not valid JSON: %v", path, err)
+	}
+
+	keys := Keys{}
+	for i, c := range kf.Callers {
+		if c.Name == "" || c.Key == "" {
+			return nil, fmt.Errorf("caller #%d in %v needs both a name and a key", i+1, path)
+		}
+		if _, ok := keys[c.Key]; ok {
+			return nil, fmt.Errorf("caller %v in %v has the same key as another caller", c.Name, path)
+		}
+		caller := Caller{Name: c.Name}
+		switch {
+		case c.Token != "":
+			caller.Tokens = favor.StaticToken(c.Token)
+		case c.TokenFile != "":
+			caller.Tokens = favor.FileToken{Path: c.TokenFile}
+		default:
+			return nil, fmt.Errorf("caller %v in %v has neither a token nor a token_file", c.Name, path)
+		}
+		keys[c.Key] = caller
+	}
+	return keys, nil
+}
+
+// Server is the gateway. Keys is the only thing that has to be set.
+type Server struct {
+	Keys KeyStore
+
+	// NewClient builds the client a caller's requests are made with. It
+	// defaults to favor.NewWithTokenSource, and is mostly here for tests.
+	NewClient func(tokens favor.TokenSource) (*favor.Client, error)
+
+	// Logger gets a line for every request. Defaults to stderr.
+	Logger *log.Logger
+
+	// Now is used to figure out which merchants are open.
+	Now func() time.Time
+}
+
+// apiError is the body of every response that isn't a success.
+type apiError struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// statusError is an error that knows which status code it deserves.
+type statusError struct {
+	status int
+	code   string
+	err    error
+}
+
+func (e statusError) Error() string {
+	return e.err.Error()
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return statusError{http.StatusBadRequest, "bad_request", fmt.Errorf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) error {
+	return statusError{http.StatusNotFound, "not_found", fmt.Errorf(format, args...)}
+}
+
+// upstream wraps errors from the Favor API. Things that don't exist are passed
+// along as they are, but as far as callers are concerned, anything else means
+// it's a gateway that had a bad time. That includes Favor turning down the
+// token, which isn't the same as the caller's API key being wrong, so it gets
+// its own code. Favors nobody delivers to are on the caller, though.
+func upstream(err error) error {
+	if err == nil {
+		return nil
+	}
+	nm := &favor.NoMarketError{}
+	if errors.As(err, &nm) {
+		return badRequest("%v", err)
+	}
+	se := &favor.StatusError{}
+	if errors.As(err, &se) {
+		switch se.StatusCode {
+		case http.StatusNotFound:
+			return statusError{http.StatusNotFound, "not_found", err}
+		case http.StatusUnauthorized:
+			return statusError{http.StatusBadGateway, "upstream_unauthorized", err}
+		}
+	}
+	return statusError{http.StatusBadGateway, "upstream_error", err}
+}
+
+// request is everything a handler needs.
+type request struct {
+	*http.Request
+	caller Caller
+	client *favor.Client
+	params map[string]string
+}
+
+// handler does the work for a route, and returns what to respond with.
+type handler func(r request) (status int, body interface{}, err error)
+
+type route struct {
+	method   string
+	segments []string
+	handle   handler
+}
+
+// match returns the path parameters if the path fits the route.
+func (rt route) match(path []string) (map[string]string, bool) {
+	if len(path) != len(rt.segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, s := range rt.segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			params[s[1:len(s)-1]] = path[i]
+		} else if s != path[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func splitPath(p string) []string {
+	return strings.Split(strings.Trim(p, "/"), "/")
+}
+
+func (s *Server) routes() []route {
+	rs := []struct {
+		method, pattern string
+		handle          handler
+	}{
+		{http.MethodGet, "/v1/favors", s.listFavors},
+		{http.MethodPost, "/v1/favors", s.placeFavor},
+		{http.MethodGet, "/v1/favors/{id}", s.getFavor},
+		{http.MethodGet, "/v1/merchants", s.listMerchants},
+		{http.MethodGet, "/v1/merchants/{id}", s.getMerchant},
+		{http.MethodGet, "/v1/merchants/{id}/menu", s.getMenu},
+		{http.MethodGet, "/v1/markets", s.listMarkets},
+		{http.MethodGet, "/v1/me", s.getMe},
+		{http.MethodPatch, "/v1/me", s.updateMe},
+		{http.MethodGet, "/v1/addresses", s.listAddresses},
+		{http.MethodPost, "/v1/addresses", s.createAddress},
+		{http.MethodPut, "/v1/addresses/{id}", s.updateAddress},
+		{http.MethodDelete, "/v1/addresses/{id}", s.deleteAddress},
+	}
+	routes := []route{}
+	for _, r := range rs {
+		routes = append(routes, route{r.method, splitPath(r.pattern), r.handle})
+	}
+	return routes
+}
+
+// Handler returns the gateway as an http.Handler.
+func (s *Server) Handler() http.Handler {
+	if s.NewClient == nil {
+		s.NewClient = favor.NewWithTokenSource
+	}
+	if s.Logger == nil {
+		s.Logger = log.New(os.Stderr, "favord ", log.LstdFlags)
+	}
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+
+	routes := s.routes()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		caller := s.serve(rec, r, routes)
+		if caller == "" {
+			caller = "-"
+		}
+		s.Logger.Printf("%v %v %v %d %v", caller, r.Method, r.URL.Path, rec.status, time.Since(started))
+	})
+}
+
+// serve handles a request, and returns the name of the caller if we got far
+// enough to know who they are.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, routes []route) string {
+	switch r.URL.Path {
+	case "/openapi.json":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, []string{http.MethodGet})
+			return ""
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, OpenAPI)
+		return ""
+	case "/healthz":
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		return ""
+	}
+
+	path := splitPath(r.URL.Path)
+	var matched *route
+	var params map[string]string
+	allowed := []string{}
+	for i := range routes {
+		p, ok := routes[i].match(path)
+		if !ok {
+			continue
+		}
+		allowed = append(allowed, routes[i].method)
+		if routes[i].method == r.Method {
+			matched, params = &routes[i], p
+		}
+	}
+	if matched == nil {
+		if len(allowed) > 0 {
+			methodNotAllowed(w, allowed)
+		} else {
+			writeError(w, notFound("there's nothing at %v", r.URL.Path))
+		}
+		return ""
+	}
+
+	caller, ok := s.Keys.Lookup(apiKey(r))
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="favord"`)
+		writeError(w, statusError{http.StatusUnauthorized, "unauthorized", fmt.Errorf("a valid API key is required")})
+		return ""
+	}
+	client, err := s.NewClient(caller.Tokens)
+	if err != nil {
+		writeError(w, fmt.Errorf("building a client for %v failed: %v", caller.Name, err))
+		return caller.Name
+	}
+
+	status, body, err := matched.handle(request{Request: r, caller: caller, client: client, params: params})
+	if err != nil {
+		writeError(w, err)
+		return caller.Name
+	}
+	writeJSON(w, status, body)
+	return caller.Name
+}
+
+// apiKey pulls the API key out of a request, from either the Authorization or
+// X-API-Key header.
+func apiKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	se, ok := err.(statusError)
+	if !ok {
+		se = statusError{http.StatusInternalServerError, "internal_error", err}
+	}
+	body := apiError{}
+	body.Error.Code = se.code
+	body.Error.Message = se.err.Error()
+	writeJSON(w, se.status, body)
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed []string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(w, statusError{http.StatusMethodNotAllowed, "method_not_allowed", fmt.Errorf("try one of %v", strings.Join(allowed, ", "))})
+}
+
+// decode reads a JSON request body, and refuses fields it doesn't know about,
+// so that typos don't get silently ignored.
+func decode(r request, v interface{}) error {
+	if ct := r.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "application/json") {
+		return statusError{http.StatusUnsupportedMediaType, "unsupported_media_type", fmt.Errorf("expected application/json, not %v", ct)}
+	}
+	d := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<20))
+	d.DisallowUnknownFields()
+	if err := d.Decode(v); err != nil {
+		return badRequest("the request body isn't valid: %v", err)
+	}
+	return nil
+}
+
+// statusRecorder remembers the status code, for logging.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,322 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/favortest"
+)
+
+var dummyToken = "thisisarandomstringfortestinglol"
+
+const dummyKey = "letmein"
+
+// upstreamAPI pretends to be the Favor API. Whatever it's sent gets written to
+// received, keyed by method and path.
+func upstreamAPI(received map[string]url.Values) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		received[r.Method+" "+r.URL.Path] = r.PostForm
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v5/favors/":
+			fmt.Fprintln(w, `{"count": 1, "favors": [{"id": "9876", "title": "Torchy's Tacos", "stage": "delivered", "created_at": 1457524800, "receipt": {"price": "10.00", "tip": "2.00", "delivery_charge": "1.50", "cc_fee_amount": "0.35"}}]}`)
+		case "POST /api/v5/favors/":
+			fmt.Fprintln(w, `{"favor": {"id": "9877", "title": "Torchy's Tacos", "stage": "pending"}}`)
+		case "GET /api/v5/favors/9876":
+			fmt.Fprintln(w, `{"favor": {"id": "9876", "title": "Torchy's Tacos", "stage": "assigned", "runner": {"id": "77", "forename": "Speedy"}}}`)
+		case "GET /api/v5/markets":
+			fmt.Fprintln(w, `{"markets": [{"id": "7", "name": "Austin", "lat": "30.2672", "lng": "-97.7431", "radius": 40000}]}`)
+		case "GET /api/v5/merchants":
+			fmt.Fprintln(w, `{"merchants": [
+				{"id": "1", "name": "Far Away Tacos", "lat": "30.4", "lng": "-97.7"},
+				{"id": "2", "name": "Torchy's Tacos", "lat": "30.2672", "lng": "-97.7431", "has_expanded_menu": "1"}
+			]}`)
+		case "GET /api/v5/me":
+			fmt.Fprintln(w, `{"user": {"id": "1234", "forename": "Greg", "surname": "Salt", "email": "greg@example.com"}}`)
+		case "PUT /api/v5/me":
+			// like the real thing, only what's sent is changed
+			me := map[string]string{"forename": "Greg", "surname": "Salt", "email": "greg@example.com"}
+			for field := range me {
+				if v := r.PostForm.Get(field); v != "" {
+					me[field] = v
+				}
+			}
+			fmt.Fprintf(w, `{"user": {"id": "1234", "forename": %q, "surname": %q, "email": %q}}`, me["forename"], me["surname"], me["email"])
+		case "DELETE /api/v5/addresses/55":
+			fmt.Fprintln(w, `{}`)
+		case "GET /api/v5/favors/404":
+			// the Favor API doesn't 404 for favors it can't find
+			fmt.Fprintln(w, `{"favor": null}`)
+		case "GET /api/v5/merchant/404":
+			fmt.Fprintln(w, `{"merchant": null}`)
+		case "GET /api/v5/merchant/401":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "GET /api/v5/merchant/500":
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintln(w, `<html>oh no</html>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+func buildTestServer(t *testing.T, handler http.HandlerFunc) http.Handler {
+	upstream := favortest.NewClient(t, handler)
+	s := &Server{
+		Keys: Keys{dummyKey: Caller{Name: "billing", Tokens: favor.StaticToken(dummyToken)}},
+		NewClient: func(tokens favor.TokenSource) (*favor.Client, error) {
+			client := *upstream
+			client.TokenSource = tokens
+			return &client, nil
+		},
+		Logger: log.New(ioutil.Discard, "", 0),
+		Now:    func() time.Time { return time.Date(2016, time.March, 9, 12, 0, 0, 0, time.UTC) },
+	}
+	return s.Handler()
+}
+
+func call(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+dummyKey)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	body := apiError{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body), "Error responses should be JSON")
+	return body.Error.Code
+}
+
+func TestAuthentication(t *testing.T) {
+	h := buildTestServer(t, upstreamAPI(map[string]url.Values{}))
+
+	req := httptest.NewRequest("GET", "/v1/favors", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "unauthorized", errorCode(t, w))
+	assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+
+	req.Header.Set("X-API-Key", "letmeinplease")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req.Header.Set("X-API-Key", dummyKey)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "The OpenAPI document shouldn't need a key")
+}
+
+func TestRouting(t *testing.T) {
+	h := buildTestServer(t, upstreamAPI(map[string]url.Values{}))
+
+	w := call(h, "GET", "/v1/tacos", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "not_found", errorCode(t, w))
+
+	w = call(h, "DELETE", "/v1/favors", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, POST", w.Header().Get("Allow"))
+}
+
+func TestFavors(t *testing.T) {
+	received := map[string]url.Values{}
+	h := buildTestServer(t, upstreamAPI(received))
+
+	w := call(h, "GET", "/v1/favors", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	list := struct {
+		Favors []Favor `json:"favors"`
+	}{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &list))
+	if assert.Len(t, list.Favors, 1) {
+		assert.True(t, list.Favors[0].Finished)
+		assert.Equal(t, int64(1385), list.Favors[0].Receipt.TotalCents)
+		assert.Equal(t, "2016-03-09T12:00:00Z", list.Favors[0].CreatedAt.Format(time.RFC3339))
+	}
+
+	w = call(h, "GET", "/v1/favors/9876", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	f := Favor{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &f))
+	assert.Equal(t, "Speedy", f.Runner.Forename)
+	assert.Nil(t, f.Receipt, "Favors that haven't been paid for shouldn't have a receipt")
+
+	w = call(h, "GET", "/v1/favors/404", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = call(h, "GET", "/v1/favors/gone", "")
+	assert.Equal(t, http.StatusNotFound, w.Code, "Actual 404s should be passed along too")
+
+	w = call(h, "POST", "/v1/favors", `{"title": "Torchy's Tacos", "items": ["2x Trailer Park"], "street": "123 Fake St", "zipcode": "78701"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "A favor without a location should be refused")
+
+	w = call(h, "POST", "/v1/favors", `{"title": "Torchy's Tacos", "wnats": "tacos"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Unknown fields should be refused")
+
+	w = call(h, "POST", "/v1/favors", `{"title": "Torchy's Tacos", "items": ["2x Trailer Park", "1x Queso"], "location": {"lat": 30.2672, "lng": -97.7431}, "street": "123 Fake St", "zipcode": "78701", "merchant_id": "2"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	placed := received["POST /api/v5/favors/"]
+	assert.Equal(t, "2x Trailer Park\n1x Queso", placed.Get("wants"))
+	assert.Equal(t, "2", placed.Get("merchant_id"))
+	assert.Equal(t, "7", placed.Get("market_id"), "The market should be figured out from the location")
+
+	delete(received, "POST /api/v5/favors/")
+	w = call(h, "POST", "/v1/favors", `{"title": "Joe's Pizza", "items": ["1x Slice"], "location": {"lat": 40.7128, "lng": -74.006}, "street": "7 Carmine St", "zipcode": "10014"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Places no market serves should be refused")
+	_, ok := received["POST /api/v5/favors/"]
+	assert.False(t, ok, "Favors no market serves shouldn't be placed")
+}
+
+func TestMerchants(t *testing.T) {
+	h := buildTestServer(t, upstreamAPI(map[string]url.Values{}))
+
+	w := call(h, "GET", "/v1/merchants?lat=30.2672&lng=-97.7431", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	list := struct {
+		Merchants []Merchant `json:"merchants"`
+	}{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &list))
+	if assert.Len(t, list.Merchants, 2) {
+		assert.Equal(t, "Torchy's Tacos", list.Merchants[0].Name, "Merchants should be closest first")
+		assert.True(t, list.Merchants[0].ExpandedMenu)
+		assert.NotNil(t, list.Merchants[0].DistanceMeters)
+	}
+
+	w = call(h, "GET", "/v1/merchants?lat=30.2672&lng=-97.7431&q=far+awya", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &list))
+	if assert.NotEmpty(t, list.Merchants) {
+		assert.Equal(t, "Far Away Tacos", list.Merchants[0].Name)
+	}
+
+	w = call(h, "GET", "/v1/merchants?lat=north", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = call(h, "GET", "/v1/merchants/404", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "not_found", errorCode(t, w))
+
+	w = call(h, "GET", "/v1/merchants/gone", "")
+	assert.Equal(t, http.StatusNotFound, w.Code, "Things the Favor API can't find shouldn't be the gateway's fault")
+	assert.Equal(t, "not_found", errorCode(t, w))
+
+	w = call(h, "GET", "/v1/merchants/401", "")
+	assert.Equal(t, http.StatusBadGateway, w.Code, "Favor turning down the token isn't the caller's API key being wrong")
+	assert.Equal(t, "upstream_unauthorized", errorCode(t, w))
+
+	w = call(h, "GET", "/v1/merchants/500", "")
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Equal(t, "upstream_error", errorCode(t, w))
+}
+
+func TestMeAndAddresses(t *testing.T) {
+	received := map[string]url.Values{}
+	h := buildTestServer(t, upstreamAPI(received))
+
+	w := call(h, "PATCH", "/v1/me", `{"email": "greg@example.org"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	u := User{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &u))
+	assert.Equal(t, "greg@example.org", u.Email)
+	assert.Equal(t, "Greg", u.Forename, "Fields left out of a PATCH shouldn't change")
+	assert.Equal(t, url.Values{"email": {"greg@example.org"}}, received["PUT /api/v5/me"], "Only the fields in the PATCH should be sent")
+
+	w = call(h, "DELETE", "/v1/addresses/55", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = call(h, "POST", "/v1/addresses", `{"street": "123 Fake St"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "zipcode, location")
+
+	w = call(h, "PUT", "/v1/addresses/55", `{"id": "56", "street": "123 Fake St", "zipcode": "78701", "location": {"lat": 30.2672, "lng": -97.7431}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "The body and the path should agree on the ID")
+}
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	doc := struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}{}
+	if err := json.Unmarshal([]byte(OpenAPI), &doc); err != nil {
+		t.Errorf("The OpenAPI document isn't valid JSON: %v", err)
+		t.FailNow()
+	}
+
+	documented := 0
+	for _, op := range doc.Paths {
+		for method := range op {
+			if method != "parameters" {
+				documented++
+			}
+		}
+	}
+	routes := (&Server{}).routes()
+	for _, r := range routes {
+		path := "/" + strings.Join(r.segments, "/")
+		_, ok := doc.Paths[path][strings.ToLower(r.method)]
+		assert.True(t, ok, "%v %v isn't in the OpenAPI document", r.method, path)
+	}
+	assert.Equal(t, len(routes), documented, "The OpenAPI document has operations that aren't routed")
+}
+
+func TestLoadKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "favord")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys.json")
+
+	_, err = LoadKeys(path)
+	assert.NotNil(t, err, "A missing key file should be an error")
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`{"callers": [
+		{"name": "billing", "key": "one", "token": "thisisarandomstringfortestinglol"},
+		{"name": "bots", "key": "two", "token_file": "/etc/favor/token"}
+	]}`), 0600))
+	keys, err := LoadKeys(path)
+	assert.Nil(t, err)
+	caller, ok := keys.Lookup("two")
+	assert.True(t, ok)
+	assert.Equal(t, "bots", caller.Name)
+	assert.Equal(t, favor.FileToken{Path: "/etc/favor/token"}, caller.Tokens)
+	_, ok = keys.Lookup("three")
+	assert.False(t, ok)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`{"callers": [{"name": "a", "key": "one", "token": "x"}, {"name": "b", "key": "one", "token": "y"}]}`), 0600))
+	_, err = LoadKeys(path)
+	assert.NotNil(t, err, "Two callers sharing a key should be an error")
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`{"callers": [{"name": "a", "key": "one"}]}`), 0600))
+	_, err = LoadKeys(path)
+	assert.NotNil(t, err, "A caller without a token should be an error")
+}
+
+func TestLogging(t *testing.T) {
+	buf := &bytes.Buffer{}
+	s := &Server{Keys: Keys{}, Logger: log.New(buf, "", 0)}
+	s.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/v1/favors", nil))
+	assert.True(t, strings.HasPrefix(buf.String(), "- GET /v1/favors 401 "), "Unexpected log line %q", buf.String())
+}
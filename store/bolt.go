@@ -0,0 +1,226 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// These are the buckets a BoltStore keeps things in. Favors are keyed by ID.
+// Snapshots get a bucket per favor, keyed by sequence number, so they come
+// back out in the order they went in. Seen indexes favors by when they were
+// last synced, so queries with SeenSince don't have to read every favor.
+var (
+	favorsBucket    = []byte("favors")
+	snapshotsBucket = []byte("snapshots")
+	seenBucket      = []byte("seen")
+	metaBucket      = []byte("meta")
+	lastSyncKey     = []byte("last_sync")
+)
+
+// seenKey is the key for a favor in the seen bucket. Keys sort by time, and
+// then by ID.
+func seenKey(t time.Time, id string) []byte {
+	key := make([]byte, 8, 8+len(id))
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return append(key, id...)
+}
+
+// BoltStore is a Store kept in a single bbolt file.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore opens the store at path, creating it if it doesn't exist. Only
+// one process can have a store open at a time; anyone else waits up to a
+// second before giving up.
+func OpenBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		indexed := tx.Bucket(seenBucket) != nil
+		for _, name := range [][]byte{favorsBucket, snapshotsBucket, seenBucket, metaBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		if indexed {
+			return nil
+		}
+		// Stores from before there was an index need one built.
+		seen := tx.Bucket(seenBucket)
+		return tx.Bucket(favorsBucket).ForEach(func(k, v []byte) error {
+			r := Record{}
+			if err := json.Unmarshal(v, &r); err != nil {
+				return err
+			}
+			return seen.Put(seenKey(r.LastSeen, string(k)), nil)
+		})
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Upsert adds or updates a favor.
+func (b *BoltStore) Upsert(f favor.Favor, at time.Time) (Change, error) {
+	change := Unchanged
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		favors := tx.Bucket(favorsBucket)
+		var existing *Record
+		if data := favors.Get([]byte(f.ID)); data != nil {
+			existing = &Record{}
+			if err := json.Unmarshal(data, existing); err != nil {
+				return err
+			}
+		}
+
+		r, snapshot, c := upsert(existing, f, at)
+		change = c
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		if err := favors.Put([]byte(f.ID), data); err != nil {
+			return err
+		}
+		seen := tx.Bucket(seenBucket)
+		if existing != nil {
+			if err := seen.Delete(seenKey(existing.LastSeen, f.ID)); err != nil {
+				return err
+			}
+		}
+		if err := seen.Put(seenKey(r.LastSeen, f.ID), nil); err != nil {
+			return err
+		}
+		if snapshot == nil {
+			return nil
+		}
+
+		snapshots, err := tx.Bucket(snapshotsBucket).CreateBucketIfNotExists([]byte(f.ID))
+		if err != nil {
+			return err
+		}
+		seq, err := snapshots.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+		data, err = json.Marshal(snapshot)
+		if err != nil {
+			return err
+		}
+		return snapshots.Put(key, data)
+	})
+	return change, err
+}
+
+// Get returns the record for a favor, and whether there is one.
+func (b *BoltStore) Get(id string) (Record, bool, error) {
+	r := Record{}
+	found := false
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(favorsBucket).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &r)
+	})
+	return r, found, err
+}
+
+// Query returns the records that fit the query.
+func (b *BoltStore) Query(q Query) ([]Record, error) {
+	records := []Record{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		favors := tx.Bucket(favorsBucket)
+		check := func(v []byte) error {
+			r := Record{}
+			if err := json.Unmarshal(v, &r); err != nil {
+				return err
+			}
+			if q.Matches(r) {
+				records = append(records, r)
+			}
+			return nil
+		}
+		if q.SeenSince.IsZero() {
+			return favors.ForEach(func(k, v []byte) error {
+				return check(v)
+			})
+		}
+
+		c := tx.Bucket(seenBucket).Cursor()
+		for k, _ := c.Seek(seenKey(q.SeenSince, "")); k != nil; k, _ = c.Next() {
+			if v := favors.Get(k[8:]); v != nil {
+				if err := check(v); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return q.apply(records), nil
+}
+
+// Snapshots returns every snapshot of a favor, oldest first.
+func (b *BoltStore) Snapshots(id string) ([]Snapshot, error) {
+	snapshots := []Snapshot{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(snapshotsBucket).Bucket([]byte(id))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			s := Snapshot{}
+			if err := json.Unmarshal(v, &s); err != nil {
+				return err
+			}
+			snapshots = append(snapshots, s)
+			return nil
+		})
+	})
+	return snapshots, err
+}
+
+// LastSync returns when the store was last synced.
+func (b *BoltStore) LastSync() (time.Time, error) {
+	t := time.Time{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(metaBucket).Get(lastSyncKey)
+		if data == nil {
+			return nil
+		}
+		return t.UnmarshalText(data)
+	})
+	return t, err
+}
+
+// SetLastSync records when the store was last synced.
+func (b *BoltStore) SetLastSync(t time.Time) error {
+	data, err := t.MarshalText()
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(metaBucket).Put(lastSyncKey, data)
+	})
+}
+
+// Close closes the file.
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
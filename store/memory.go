@@ -0,0 +1,86 @@
+package store
+
+import (
+	"sync"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// MemoryStore is a Store that forgets everything when the program exits.
+type MemoryStore struct {
+	lock      sync.Mutex
+	records   map[string]Record
+	snapshots map[string][]Snapshot
+	lastSync  time.Time
+}
+
+// NewMemoryStore is a constructor function returning an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: map[string]Record{}, snapshots: map[string][]Snapshot{}}
+}
+
+// Upsert adds or updates a favor.
+func (m *MemoryStore) Upsert(f favor.Favor, at time.Time) (Change, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var existing *Record
+	if r, ok := m.records[f.ID]; ok {
+		existing = &r
+	}
+	r, snapshot, change := upsert(existing, f, at)
+	m.records[f.ID] = r
+	if snapshot != nil {
+		m.snapshots[f.ID] = append(m.snapshots[f.ID], *snapshot)
+	}
+	return change, nil
+}
+
+// Get returns the record for a favor, and whether there is one.
+func (m *MemoryStore) Get(id string) (Record, bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	r, ok := m.records[id]
+	return r, ok, nil
+}
+
+// Query returns the records that fit the query.
+func (m *MemoryStore) Query(q Query) ([]Record, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	records := []Record{}
+	for _, r := range m.records {
+		if q.Matches(r) {
+			records = append(records, r)
+		}
+	}
+	return q.apply(records), nil
+}
+
+// Snapshots returns every snapshot of a favor, oldest first.
+func (m *MemoryStore) Snapshots(id string) ([]Snapshot, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]Snapshot{}, m.snapshots[id]...), nil
+}
+
+// LastSync returns when the store was last synced.
+func (m *MemoryStore) LastSync() (time.Time, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.lastSync, nil
+}
+
+// SetLastSync records when the store was last synced.
+func (m *MemoryStore) SetLastSync(t time.Time) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.lastSync = t
+	return nil
+}
+
+// Close does nothing, since there's nothing to close.
+func (m *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,242 @@
+// Package store keeps a permanent record of favors. GetFavors only returns
+// what the server feels like showing at the moment, which is no good for
+// expense reports, so Sync copies favors into a Store as they come and go,
+// along with a snapshot every time one changes stage.
+//
+// There are two Stores: MemoryStore, for tests and anything short-lived, and
+// BoltStore, which keeps everything in a single file using bbolt, so there's
+// no database server to run and no cgo.
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// Record is a favor as the store knows it. FirstSeen and LastSeen are when it
+// was first and most recently synced.
+type Record struct {
+	Favor     favor.Favor `json:"favor"`
+	FirstSeen time.Time   `json:"first_seen"`
+	LastSeen  time.Time   `json:"last_seen"`
+}
+
+// CreatedAt returns when the favor was placed, as a time.
+func (r Record) CreatedAt() time.Time {
+	return time.Unix(int64(r.Favor.CreatedAt), 0)
+}
+
+// MerchantID returns the ID of the merchant the favor was from, which the API
+// puts in one of two places, depending on its mood.
+func (r Record) MerchantID() string {
+	if r.Favor.MerchantID != "" {
+		return r.Favor.MerchantID
+	}
+	return r.Favor.Merchant.ID
+}
+
+// Snapshot is what a favor looked like when its stage or status changed.
+type Snapshot struct {
+	Stage      string      `json:"stage"`
+	LastStatus string      `json:"last_status"`
+	At         time.Time   `json:"at"`
+	Favor      favor.Favor `json:"favor"`
+}
+
+// Change is what an upsert did.
+type Change int
+
+// These are the things an upsert can do.
+const (
+	Unchanged Change = iota
+	Added
+	Updated
+)
+
+func (c Change) String() string {
+	return [...]string{"unchanged", "added", "updated"}[c]
+}
+
+// Query narrows down which records to return. Zero fields don't narrow
+// anything down. From and To are compared to when the favor was placed, From
+// inclusive and To exclusive, and results are oldest first. SeenSince leaves
+// out records that haven't been synced since then, which stores can usually
+// answer without looking at everything else.
+type Query struct {
+	From       time.Time
+	To         time.Time
+	SeenSince  time.Time
+	MerchantID string
+	CustomerID string
+	Limit      int
+}
+
+// Matches returns whether a record fits the query.
+func (q Query) Matches(r Record) bool {
+	created := r.CreatedAt()
+	if !q.From.IsZero() && created.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !created.Before(q.To) {
+		return false
+	}
+	if !q.SeenSince.IsZero() && r.LastSeen.Before(q.SeenSince) {
+		return false
+	}
+	if q.MerchantID != "" && r.MerchantID() != q.MerchantID {
+		return false
+	}
+	if q.CustomerID != "" && r.Favor.Customer.ID != q.CustomerID {
+		return false
+	}
+	return true
+}
+
+// apply sorts and limits records that already match the query.
+func (q Query) apply(records []Record) []Record {
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Favor.CreatedAt != records[j].Favor.CreatedAt {
+			return records[i].Favor.CreatedAt < records[j].Favor.CreatedAt
+		}
+		return records[i].Favor.ID < records[j].Favor.ID
+	})
+	if q.Limit > 0 && len(records) > q.Limit {
+		records = records[:q.Limit]
+	}
+	return records
+}
+
+// Store is somewhere to keep favors.
+type Store interface {
+	// Upsert adds a favor, or updates the one with the same ID, and takes a
+	// snapshot if it's new or its stage or status changed.
+	Upsert(f favor.Favor, at time.Time) (Change, error)
+	Get(id string) (Record, bool, error)
+	Query(q Query) ([]Record, error)
+	Snapshots(id string) ([]Snapshot, error)
+	// LastSync is when Sync last finished successfully, or zero if it never
+	// has.
+	LastSync() (time.Time, error)
+	SetLastSync(t time.Time) error
+	Close() error
+}
+
+// upsert works out what Upsert should do with a favor, given the record for it
+// that's already stored, if there is one. Every Store uses this, so they all
+// agree on what counts as a change.
+func upsert(existing *Record, f favor.Favor, at time.Time) (Record, *Snapshot, Change) {
+	snapshot := &Snapshot{Stage: f.Stage, LastStatus: f.LastStatus, At: at, Favor: f}
+	if existing == nil {
+		return Record{Favor: f, FirstSeen: at, LastSeen: at}, snapshot, Added
+	}
+
+	r := *existing
+	r.LastSeen = at
+	changes := f.ChangesFrom(existing.Favor)
+	if !changes.Stage && !changes.LastStatus {
+		snapshot = nil
+	}
+	if favorsEqual(existing.Favor, f) {
+		return r, nil, Unchanged
+	}
+	r.Favor = f
+	return r, snapshot, Updated
+}
+
+// favorsEqual compares favors by their JSON, since that's how they're stored,
+// and things like times don't always come back out exactly as they went in.
+func favorsEqual(a, b favor.Favor) bool {
+	aj, aerr := json.Marshal(a)
+	bj, berr := json.Marshal(b)
+	return aerr == nil && berr == nil && bytes.Equal(aj, bj)
+}
+
+// SyncResult counts what a sync did.
+type SyncResult struct {
+	Added     int
+	Updated   int
+	Unchanged int
+}
+
+func (sr SyncResult) String() string {
+	return fmt.Sprintf("%d added, %d updated, %d unchanged", sr.Added, sr.Updated, sr.Unchanged)
+}
+
+func (sr *SyncResult) count(c Change) {
+	switch c {
+	case Added:
+		sr.Added++
+	case Updated:
+		sr.Updated++
+	default:
+		sr.Unchanged++
+	}
+}
+
+// Sync copies a client's favors into the store. Stored favors that haven't
+// finished yet but aren't in GetFavors anymore are looked up one at a time,
+// so that they don't get stuck halfway forever. Favors that haven't changed
+// since the last sync are left alone.
+//
+// Every sync that finishes successfully has seen every unfinished favor, so
+// only favors seen since the last one are considered when looking for the
+// unfinished ones. Anything older was already finished by then.
+func Sync(c *favor.Client, s Store) (SyncResult, error) {
+	result := SyncResult{}
+	since, err := s.LastSync()
+	if err != nil {
+		return result, err
+	}
+	favors, err := c.GetFavors()
+	if err != nil {
+		return result, err
+	}
+	now := time.Now()
+
+	seen := map[string]bool{}
+	for _, f := range favors {
+		if f.ID == "" {
+			continue
+		}
+		seen[f.ID] = true
+		change, err := s.Upsert(f, now)
+		if err != nil {
+			return result, err
+		}
+		result.count(change)
+	}
+
+	unfinished, err := s.Query(Query{SeenSince: since})
+	if err != nil {
+		return result, err
+	}
+	failures := []string{}
+	for _, r := range unfinished {
+		if seen[r.Favor.ID] || r.Favor.IsFinished() {
+			continue
+		}
+		f, err := c.GetFavor(r.Favor.ID)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%v: %v", r.Favor.ID, err))
+			continue
+		}
+		if f.ID == "" {
+			continue
+		}
+		change, err := s.Upsert(f, now)
+		if err != nil {
+			return result, err
+		}
+		result.count(change)
+	}
+	if len(failures) > 0 {
+		return result, fmt.Errorf("Syncing some unfinished favors failed:\n%v", strings.Join(failures, "\n"))
+	}
+	return result, s.SetLastSync(now)
+}
@@ -0,0 +1,198 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/favortest"
+)
+
+var (
+	march = time.Date(2016, time.March, 9, 12, 0, 0, 0, time.UTC)
+	april = time.Date(2016, time.April, 9, 12, 0, 0, 0, time.UTC)
+)
+
+// testStore runs the same checks against any Store.
+func testStore(t *testing.T, s Store) {
+	spec := favortest.Spec{ID: "1", Stage: "pending", MerchantID: "2", Customer: favor.User{ID: "10"}, Created: march}
+	change, err := s.Upsert(spec.Favor(), march)
+	assert.Nil(t, err)
+	assert.Equal(t, Added, change)
+
+	change, err = s.Upsert(spec.Favor(), march.Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, Unchanged, change)
+
+	changed := spec.Favor()
+	changed.Title = "Torchy's Tacos (again)"
+	change, err = s.Upsert(changed, march.Add(2*time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, Updated, change)
+
+	spec.Stage = "delivered"
+	change, err = s.Upsert(spec.Favor(), march.Add(time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, Updated, change)
+
+	r, ok, err := s.Get("1")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "delivered", r.Favor.Stage)
+	assert.True(t, r.FirstSeen.Equal(march))
+	assert.True(t, r.LastSeen.Equal(march.Add(time.Hour)))
+	_, ok, err = s.Get("404")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	snapshots, err := s.Snapshots("1")
+	assert.Nil(t, err)
+	if assert.Len(t, snapshots, 2, "Only stage changes should be snapshotted") {
+		assert.Equal(t, "pending", snapshots[0].Stage)
+		assert.Equal(t, "delivered", snapshots[1].Stage)
+		assert.True(t, snapshots[1].At.Equal(march.Add(time.Hour)))
+	}
+
+	s.Upsert(favortest.Spec{ID: "2", Stage: "delivered", MerchantID: "3", Customer: favor.User{ID: "10"}, Created: april}.Favor(), april)
+	s.Upsert(favortest.Spec{ID: "3", Stage: "delivered", MerchantID: "2", Customer: favor.User{ID: "11"}, Created: april.Add(time.Hour)}.Favor(), april)
+
+	ids := func(q Query) []string {
+		records, err := s.Query(q)
+		assert.Nil(t, err)
+		out := []string{}
+		for _, r := range records {
+			out = append(out, r.Favor.ID)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"1", "2", "3"}, ids(Query{}))
+	assert.Equal(t, []string{"2", "3"}, ids(Query{From: april}))
+	assert.Equal(t, []string{"1"}, ids(Query{To: april}))
+	assert.Equal(t, []string{"1", "3"}, ids(Query{MerchantID: "2"}))
+	assert.Equal(t, []string{"3"}, ids(Query{CustomerID: "11"}))
+	assert.Equal(t, []string{"1", "2"}, ids(Query{Limit: 2}))
+	assert.Equal(t, []string{"2", "3"}, ids(Query{SeenSince: april}))
+	assert.Equal(t, []string{"1", "2", "3"}, ids(Query{SeenSince: march.Add(time.Hour)}))
+	assert.Empty(t, ids(Query{SeenSince: april.Add(time.Second)}))
+
+	last, err := s.LastSync()
+	assert.Nil(t, err)
+	assert.True(t, last.IsZero())
+	assert.Nil(t, s.SetLastSync(april))
+	last, err = s.LastSync()
+	assert.Nil(t, err)
+	assert.True(t, last.Equal(april))
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestBoltStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "favors.db")
+
+	s, err := OpenBoltStore(path)
+	assert.Nil(t, err)
+	testStore(t, s)
+	assert.Nil(t, s.Close())
+
+	s, err = OpenBoltStore(path)
+	assert.Nil(t, err)
+	defer s.Close()
+	records, err := s.Query(Query{})
+	assert.Nil(t, err)
+	assert.Len(t, records, 3, "Records should survive being closed and opened again")
+	snapshots, err := s.Snapshots("1")
+	assert.Nil(t, err)
+	assert.Len(t, snapshots, 2)
+}
+
+func TestBoltStoreBuildsItsIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "favors.db")
+
+	// this is what stores looked like before the seen index
+	db, err := bolt.Open(path, 0600, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Update(func(tx *bolt.Tx) error {
+		favors, err := tx.CreateBucket(favorsBucket)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(Record{Favor: favortest.Spec{ID: "1", Stage: "pending", MerchantID: "2", Customer: favor.User{ID: "10"}, Created: march}.Favor(), FirstSeen: april, LastSeen: april})
+		if err != nil {
+			return err
+		}
+		return favors.Put([]byte("1"), data)
+	}))
+	assert.Nil(t, db.Close())
+
+	s, err := OpenBoltStore(path)
+	assert.Nil(t, err)
+	defer s.Close()
+	records, err := s.Query(Query{SeenSince: april})
+	assert.Nil(t, err)
+	assert.Len(t, records, 1, "Favors stored before the index should be indexed when the store is opened")
+}
+
+func TestSync(t *testing.T) {
+	var lock sync.Mutex
+	list := `[{"id": "1", "stage": "pending"}, {"id": "2", "stage": "delivered"}]`
+	single := `{"id": "1", "stage": "pending"}`
+	lookups := map[string]int{}
+	c := favortest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		lookups[r.URL.Path]++
+		if r.URL.Path == "/api/v5/favors/" {
+			fmt.Fprintf(w, `{"favors": %v}`, list)
+		} else {
+			fmt.Fprintf(w, `{"favor": %v}`, single)
+		}
+	}))
+
+	s := NewMemoryStore()
+	result, err := Sync(c, s)
+	assert.Nil(t, err)
+	assert.Equal(t, SyncResult{Added: 2}, result)
+
+	result, err = Sync(c, s)
+	assert.Nil(t, err)
+	assert.Equal(t, SyncResult{Unchanged: 2}, result)
+
+	lock.Lock()
+	list, single = `[{"id": "3", "stage": "pending"}]`, `{"id": "1", "stage": "delivered"}`
+	lock.Unlock()
+	result, err = Sync(c, s)
+	assert.Nil(t, err)
+	assert.Equal(t, SyncResult{Added: 1, Updated: 1}, result, "Unfinished favors should be followed after they drop off the list")
+	r, _, _ := s.Get("1")
+	assert.Equal(t, "delivered", r.Favor.Stage)
+
+	last, _ := s.LastSync()
+	assert.False(t, last.IsZero())
+
+	// a favor that was already stuck before the last sync finished isn't
+	// looked up again
+	_, err = s.Upsert(favortest.Spec{ID: "9", Stage: "pending", MerchantID: "2", Customer: favor.User{ID: "10"}, Created: march}.Favor(), last.Add(-time.Hour))
+	assert.Nil(t, err)
+	_, err = Sync(c, s)
+	assert.Nil(t, err)
+	assert.Zero(t, lookups["/api/v5/favors/9"])
+	assert.Equal(t, 1, lookups["/api/v5/favors/1"], "Favor 1 finished, so it should only have been looked up once")
+}
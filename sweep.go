@@ -0,0 +1,150 @@
+package favor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// DefaultSweepParallelism is how many GetMerchants requests a sweep will have
+// in flight at once, unless told otherwise.
+const DefaultSweepParallelism = 4
+
+// SweepOptions tweaks how a merchant sweep behaves. Progress, if set, is
+// called every time a point in the grid has been checked.
+type SweepOptions struct {
+	Parallelism int
+	Progress    func(SweepProgress)
+}
+
+// SweepProgress describes how far along a sweep is.
+type SweepProgress struct {
+	Completed int
+	Failed    int
+	Total     int
+	Found     int
+}
+
+// SweepResult is everything a sweep found, along with how much of the region
+// was actually covered. Points that failed are listed so they can be retried.
+type SweepResult struct {
+	Merchants Merchants
+	Total     int
+	Completed int
+	Failed    []LatLng
+}
+
+// Coverage returns the fraction of grid points that were successfully checked.
+func (sr SweepResult) Coverage() float64 {
+	if sr.Total == 0 {
+		return 0
+	}
+	return float64(sr.Completed) / float64(sr.Total)
+}
+
+// gridPoints tiles a bounding box with points roughly spacing meters apart,
+// starting in the south west corner and working row by row to the north east.
+func gridPoints(box BoundingBox, spacing float64) []LatLng {
+	points := []LatLng{}
+	latStep := degrees(spacing / earthRadius)
+	for lat := box.SouthWest.Lat; lat <= box.NorthEast.Lat+latStep/2; lat += latStep {
+		rowLat := math.Min(lat, box.NorthEast.Lat)
+		lngStep := degrees(spacing / (earthRadius * math.Cos(radians(rowLat))))
+		for lng := box.SouthWest.Lng; lng <= box.NorthEast.Lng+lngStep/2; lng += lngStep {
+			points = append(points, LatLng{Lat: rowLat, Lng: math.Min(lng, box.NorthEast.Lng)})
+		}
+	}
+	return points
+}
+
+// SweepMerchants discovers every merchant in a region by tiling it with points
+// gridSpacing meters apart and calling GetMerchants for each of them. Results
+// are de-duplicated by merchant ID.
+func (c Client) SweepMerchants(ctx context.Context, box BoundingBox, gridSpacing float64) (SweepResult, error) {
+	return c.SweepMerchantsWithOptions(ctx, box, gridSpacing, SweepOptions{})
+}
+
+// SweepMerchantsWithOptions is SweepMerchants, but with control over how many
+// requests are made at once and a hook for progress reporting. If the context
+// is cancelled, whatever was found so far is returned along with the context's error.
+func (c Client) SweepMerchantsWithOptions(ctx context.Context, box BoundingBox, gridSpacing float64, opts SweepOptions) (SweepResult, error) {
+	if gridSpacing <= 0 {
+		return SweepResult{}, fmt.Errorf("Grid spacing must be greater than zero, got %v.", gridSpacing)
+	}
+	if err := box.SouthWest.Validate(); err != nil {
+		return SweepResult{}, err
+	}
+	if err := box.NorthEast.Validate(); err != nil {
+		return SweepResult{}, err
+	}
+	if box.SouthWest.Lat > box.NorthEast.Lat || box.SouthWest.Lng > box.NorthEast.Lng {
+		return SweepResult{}, fmt.Errorf("The bounding box's south west corner must be south and west of its north east corner.")
+	}
+
+	parallelism := opts.Parallelism
+	if parallelism < 1 {
+		parallelism = DefaultSweepParallelism
+	}
+
+	points := gridPoints(box, gridSpacing)
+	result := SweepResult{Total: len(points)}
+	found := map[string]Merchant{}
+
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan LatLng)
+
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range queue {
+				merchants, err := c.GetMerchants(p.Lat, p.Lng)
+
+				lock.Lock()
+				if err != nil {
+					result.Failed = append(result.Failed, p)
+				} else {
+					result.Completed++
+					for _, m := range merchants {
+						found[m.ID] = m
+					}
+				}
+				progress := SweepProgress{
+					Completed: result.Completed,
+					Failed:    len(result.Failed),
+					Total:     result.Total,
+					Found:     len(found),
+				}
+				if opts.Progress != nil {
+					opts.Progress(progress)
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+
+	var err error
+dispatch:
+	for _, p := range points {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			break dispatch
+		case queue <- p:
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	for _, m := range found {
+		result.Merchants = append(result.Merchants, m)
+	}
+	sort.Sort(result.Merchants)
+	return result, err
+}
@@ -0,0 +1,55 @@
+package favor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGridPoints(t *testing.T) {
+	box := BoundingBoxAround(austin, 1000)
+	points := gridPoints(box, 500)
+	for _, p := range points {
+		assert.True(t, box.Contains(p), "Every grid point should be inside the box")
+	}
+	// a two kilometer square at 500 meter spacing should be a five by five grid
+	assert.Len(t, points, 25)
+}
+
+func TestSweepMerchants(t *testing.T) {
+	var requests int32
+
+	s := setupMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		lat, _ := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
+		// every request finds the same merchant, plus one more that depends on which row it's in
+		fmt.Fprintf(w, `{"merchants": [{"id": "1", "name": "Everywhere"}, {"id": "%v", "name": "Row %v"}]}`, lat, lat)
+	}))
+
+	progressCalls := 0
+	result, err := s.SweepMerchantsWithOptions(context.Background(), BoundingBoxAround(austin, 1000), 500, SweepOptions{
+		Parallelism: 3,
+		Progress:    func(p SweepProgress) { progressCalls++ },
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 25, result.Total)
+	assert.Equal(t, 25, result.Completed)
+	assert.Equal(t, 25, progressCalls)
+	assert.Equal(t, int32(25), atomic.LoadInt32(&requests))
+	assert.Equal(t, 1.0, result.Coverage())
+	assert.Len(t, result.Merchants, 6, "Merchants should be de-duplicated by ID")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err = s.SweepMerchants(ctx, BoundingBoxAround(austin, 1000), 500)
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, result.Coverage() < 1)
+
+	_, err = s.SweepMerchants(context.Background(), BoundingBoxAround(austin, 1000), 0)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,263 @@
+package favor
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+)
+
+// TokenSource is anything that can provide a favorToken. A Client with a
+// TokenSource asks it for a token on every request, so rotating a token
+// only has to happen in one place.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// Refresher is implemented by TokenSources that can go get a brand new token
+// when the server rejects the current one.
+type Refresher interface {
+	Refresh() error
+}
+
+// StaticToken is a TokenSource that always returns the same token.
+type StaticToken string
+
+// Token returns the token.
+func (s StaticToken) Token() (string, error) {
+	return string(s), nil
+}
+
+// EnvToken is a TokenSource that reads the token from an environment
+// variable every time it's asked.
+type EnvToken struct {
+	Name string
+}
+
+// Token returns the value of the environment variable.
+func (e EnvToken) Token() (string, error) {
+	token := strings.TrimSpace(os.Getenv(e.Name))
+	if token == "" {
+		return "", fmt.Errorf("The environment variable %v is empty.", e.Name)
+	}
+	return token, nil
+}
+
+// FileToken is a TokenSource that reads the token from a file on disk. Since
+// a token is as good as a password, the file must only be readable and
+// writable by its owner, and FileToken refuses to read it otherwise.
+type FileToken struct {
+	Path string
+}
+
+func checkTokenFilePermissions(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("The token file %v has permissions %v, but it must not be accessible to anyone but its owner (0600).", path, info.Mode().Perm())
+	}
+	return nil
+}
+
+// Token returns the contents of the file, sans whitespace.
+func (f FileToken) Token() (string, error) {
+	if err := checkTokenFilePermissions(f.Path); err != nil {
+		return "", err
+	}
+	contents, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(contents))
+	if token == "" {
+		return "", fmt.Errorf("The token file %v is empty.", f.Path)
+	}
+	return token, nil
+}
+
+// Save writes a new token to the file with 0600 permissions.
+func (f FileToken) Save(token string) error {
+	if err := ioutil.WriteFile(f.Path, []byte(token+"\n"), 0600); err != nil {
+		return err
+	}
+	// WriteFile doesn't change the permissions of files that already exist
+	return os.Chmod(f.Path, 0600)
+}
+
+// EncryptedTokenStore is a TokenSource that keeps the token in a file on disk,
+// encrypted with AES-GCM. The key is derived from the passphrase with
+// PBKDF2, using a random salt that's stored at the top of the file, so that
+// guessing the passphrase offline is slow, and has to be done for every
+// file separately. The file is held to the same 0600 standard as FileToken.
+type EncryptedTokenStore struct {
+	Path       string
+	Passphrase string
+}
+
+// encryptedTokenMagic starts every file Save writes. It's followed by the
+// PBKDF2 iteration count, the salt, the nonce and the sealed token. Files
+// without it are from before there was a salt, when the key was just a
+// SHA-256 of the passphrase; they can still be read, and are upgraded the
+// next time a token is saved.
+var encryptedTokenMagic = []byte("FTS\x02")
+
+const encryptedTokenSaltSize = 16
+
+// encryptedTokenIterations is how many rounds of PBKDF2 new files get. It's a
+// variable only so that the tests don't take forever.
+var encryptedTokenIterations = 600000
+
+func (e EncryptedTokenStore) aead(salt []byte, iterations int) (cipher.AEAD, error) {
+	if e.Passphrase == "" {
+		return nil, fmt.Errorf("A passphrase is required to use an encrypted token store.")
+	}
+	var key []byte
+	if salt == nil {
+		legacy := sha256.Sum256([]byte(e.Passphrase))
+		key = legacy[:]
+	} else {
+		var err error
+		if key, err = pbkdf2.Key(sha256.New, e.Passphrase, salt, iterations, 32); err != nil {
+			return nil, err
+		}
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Token decrypts and returns the stored token.
+func (e EncryptedTokenStore) Token() (string, error) {
+	if err := checkTokenFilePermissions(e.Path); err != nil {
+		return "", err
+	}
+	contents, err := ioutil.ReadFile(e.Path)
+	if err != nil {
+		return "", err
+	}
+
+	var salt []byte
+	iterations := 0
+	if bytes.HasPrefix(contents, encryptedTokenMagic) {
+		header := len(encryptedTokenMagic) + 4 + encryptedTokenSaltSize
+		if len(contents) < header {
+			return "", fmt.Errorf("The token store %v is too short to contain a token.", e.Path)
+		}
+		iterations = int(binary.BigEndian.Uint32(contents[len(encryptedTokenMagic):]))
+		salt = contents[header-encryptedTokenSaltSize : header]
+		contents = contents[header:]
+	}
+
+	gcm, err := e.aead(salt, iterations)
+	if err != nil {
+		return "", err
+	}
+	if len(contents) < gcm.NonceSize() {
+		return "", fmt.Errorf("The token store %v is too short to contain a token.", e.Path)
+	}
+	nonce, sealed := contents[:gcm.NonceSize()], contents[gcm.NonceSize():]
+	token, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", fmt.Errorf("The token store %v could not be decrypted, the passphrase is probably wrong.", e.Path)
+	}
+	return string(token), nil
+}
+
+// Save encrypts a new token and writes it to the store, with a new salt.
+func (e EncryptedTokenStore) Save(token string) error {
+	salt := make([]byte, encryptedTokenSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+	gcm, err := e.aead(salt, encryptedTokenIterations)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+
+	contents := append([]byte{}, encryptedTokenMagic...)
+	contents = binary.BigEndian.AppendUint32(contents, uint32(encryptedTokenIterations))
+	contents = append(contents, salt...)
+	contents = append(contents, nonce...)
+	contents = gcm.Seal(contents, nonce, []byte(token), nil)
+	if err := ioutil.WriteFile(e.Path, contents, 0600); err != nil {
+		return err
+	}
+	return os.Chmod(e.Path, 0600)
+}
+
+// ReauthTokenSource wraps another TokenSource, and when the server rejects the
+// token, calls Reauth to get a new one. If Save is set, new tokens are passed
+// to it so they survive a restart, which pairs nicely with FileToken.Save or
+// EncryptedTokenStore.Save.
+type ReauthTokenSource struct {
+	Source TokenSource
+	Reauth func() (string, error)
+	Save   func(token string) error
+
+	lock    sync.Mutex
+	current string
+	// rejected is what Source was handing out when Reauth was last needed.
+	rejected string
+}
+
+// Token returns the wrapped source's token, unless it's the one the server
+// rejected, in which case it returns the token Reauth got instead. Source is
+// asked every time, so if its token changes, say because somebody rotated
+// the file it's kept in, the new one is used.
+func (r *ReauthTokenSource) Token() (string, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	token, err := r.Source.Token()
+	if r.current == "" {
+		return token, err
+	}
+	if err == nil && token != r.rejected {
+		r.current, r.rejected = "", ""
+		return token, nil
+	}
+	return r.current, nil
+}
+
+// Refresh calls Reauth to get a new token.
+func (r *ReauthTokenSource) Refresh() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.Reauth == nil {
+		return fmt.Errorf("No reauthentication hook has been provided.")
+	}
+	token, err := r.Reauth()
+	if err != nil {
+		return err
+	}
+	if err := validateToken(token); err != nil {
+		return err
+	}
+	if r.Save != nil {
+		if err := r.Save(token); err != nil {
+			return err
+		}
+	}
+	if r.current == "" {
+		r.rejected, _ = r.Source.Token()
+	}
+	r.current = token
+	return nil
+}
@@ -0,0 +1,163 @@
+package favor
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvToken(t *testing.T) {
+	os.Setenv("FAVOR_TEST_TOKEN", dummyToken)
+	defer os.Unsetenv("FAVOR_TEST_TOKEN")
+
+	token, err := EnvToken{Name: "FAVOR_TEST_TOKEN"}.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, dummyToken, token)
+
+	_, err = EnvToken{Name: "FAVOR_TEST_TOKEN_THAT_DOESNT_EXIST"}.Token()
+	assert.NotNil(t, err)
+}
+
+func TestFileToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "favor")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	f := FileToken{Path: filepath.Join(dir, "token")}
+	assert.Nil(t, f.Save(dummyToken))
+
+	token, err := f.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, dummyToken, token)
+
+	os.Chmod(f.Path, 0644)
+	_, err = f.Token()
+	assert.NotNil(t, err, "FileToken should refuse to read world readable files")
+}
+
+func TestEncryptedTokenStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "favor")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	iterations := encryptedTokenIterations
+	encryptedTokenIterations = 1000
+	defer func() { encryptedTokenIterations = iterations }()
+
+	e := EncryptedTokenStore{Path: filepath.Join(dir, "token"), Passphrase: "hunter2"}
+	assert.Nil(t, e.Save(dummyToken))
+
+	contents, err := ioutil.ReadFile(e.Path)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(contents), dummyToken)
+	assert.True(t, bytes.HasPrefix(contents, encryptedTokenMagic))
+
+	// the same token and passphrase get a different salt every time
+	assert.Nil(t, e.Save(dummyToken))
+	again, err := ioutil.ReadFile(e.Path)
+	assert.Nil(t, err)
+	saltEnd := len(encryptedTokenMagic) + 4 + encryptedTokenSaltSize
+	assert.NotEqual(t, contents[:saltEnd], again[:saltEnd])
+
+	token, err := e.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, dummyToken, token)
+
+	_, err = EncryptedTokenStore{Path: e.Path, Passphrase: "hunter3"}.Token()
+	assert.NotNil(t, err, "The wrong passphrase shouldn't decrypt the token")
+
+	assert.Nil(t, ioutil.WriteFile(e.Path, encryptedTokenMagic, 0600))
+	_, err = e.Token()
+	assert.NotNil(t, err, "A truncated store shouldn't decrypt")
+}
+
+func TestEncryptedTokenStoreReadsUnsaltedFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "favor")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// this is how stores were written before they had a salt
+	key := sha256.Sum256([]byte("hunter2"))
+	block, err := aes.NewCipher(key[:])
+	assert.Nil(t, err)
+	gcm, err := cipher.NewGCM(block)
+	assert.Nil(t, err)
+	nonce := make([]byte, gcm.NonceSize())
+	e := EncryptedTokenStore{Path: filepath.Join(dir, "token"), Passphrase: "hunter2"}
+	assert.Nil(t, ioutil.WriteFile(e.Path, gcm.Seal(nonce, nonce, []byte(dummyToken), nil), 0600))
+
+	token, err := e.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, dummyToken, token)
+}
+
+func TestReauthTokenSourceGoesBackToSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "favor")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := FileToken{Path: filepath.Join(dir, "token")}
+	assert.Nil(t, file.Save(dummyToken))
+	reauthed := "thisisanotherrandomstringfortest"
+	ts := &ReauthTokenSource{Source: file, Reauth: func() (string, error) { return reauthed, nil }}
+
+	token, err := ts.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, dummyToken, token)
+
+	assert.Nil(t, ts.Refresh())
+	token, err = ts.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, reauthed, token, "The rejected token shouldn't be handed out again")
+
+	rotated := "thisisyetanotherrandomstringlol!"
+	assert.Nil(t, file.Save(rotated))
+	token, err = ts.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, rotated, token, "A new token in the source should be used once it shows up")
+
+	// and if the source's token is rejected too, it's back to Reauth
+	assert.Nil(t, ts.Refresh())
+	token, err = ts.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, reauthed, token)
+}
+
+func TestReauthOnUnauthorized(t *testing.T) {
+	newToken := "thisisanotherrandomstringfortest"
+
+	s := setupMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("favorToken") != newToken {
+			w.WriteHeader(401)
+			return
+		}
+		fmt.Fprintln(w, `{"user": {"id": "1234"}}`)
+	}))
+
+	reauths := 0
+	s.TokenSource = &ReauthTokenSource{
+		Source: StaticToken(dummyToken),
+		Reauth: func() (string, error) {
+			reauths++
+			return newToken, nil
+		},
+	}
+
+	u, err := s.GetMe()
+	assert.Nil(t, err)
+	assert.Equal(t, "1234", u.ID)
+	assert.Equal(t, 1, reauths)
+
+	s.TokenSource = StaticToken(dummyToken)
+	_, err = s.GetMe()
+	assert.NotNil(t, err, "Unauthorized requests should fail when the token can't be refreshed")
+}
@@ -0,0 +1,335 @@
+// Package tui is an interactive terminal interface for ordering with Favor.
+// It walks a person through picking a nearby merchant, writing up what they
+// want, confirming the order, and then follows the favor until it shows up.
+// It only reads lines and writes text, so it works in any terminal, and over
+// anything else that looks like one.
+package tui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// pageSize is how many merchants are listed at a time.
+const pageSize = 10
+
+// errQuit is returned internally when the person asks to leave.
+var errQuit = fmt.Errorf("quit")
+
+// UI is an interactive ordering session. Address is where the order will be
+// delivered; anything missing from it will be asked for.
+type UI struct {
+	Client        *favor.Client
+	In            io.Reader
+	Out           io.Writer
+	Address       favor.Address
+	WatchInterval time.Duration
+
+	// Now is used to figure out which merchants are open. It's only here so
+	// tests don't depend on what time it is.
+	Now func() time.Time
+
+	// The lines come from a goroutine, so that a prompt can give up when
+	// the context is done instead of waiting on a read that never returns.
+	ctx   context.Context
+	lines chan line
+}
+
+// line is one line read from In, or the error that stopped the reading.
+type line struct {
+	text string
+	err  error
+}
+
+func (u *UI) printf(format string, args ...interface{}) {
+	fmt.Fprintf(u.Out, format, args...)
+}
+
+// prompt asks a question and returns the trimmed answer. If the context is
+// done while it's waiting, that's taken as the person quitting.
+func (u *UI) prompt(format string, args ...interface{}) (string, error) {
+	u.printf(format, args...)
+	select {
+	case <-u.ctx.Done():
+		u.printf("\n")
+		return "", errQuit
+	case l, ok := <-u.lines:
+		if !ok {
+			return "", errQuit
+		}
+		if l.err != nil {
+			return "", l.err
+		}
+		return strings.TrimSpace(l.text), nil
+	}
+}
+
+// read sends every line from In to u.lines until it runs out, or the
+// context is done.
+func (u *UI) read(lines chan<- line) {
+	defer close(lines)
+	scanner := bufio.NewScanner(u.In)
+	for scanner.Scan() {
+		select {
+		case lines <- line{text: scanner.Text()}:
+		case <-u.ctx.Done():
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		select {
+		case lines <- line{err: err}:
+		case <-u.ctx.Done():
+		}
+	}
+}
+
+// Run starts the session, and returns once the favor has been delivered, the
+// person quits, or the context is done. Quitting isn't considered an error.
+func (u *UI) Run(ctx context.Context, near favor.LatLng) error {
+	if u.Now == nil {
+		u.Now = time.Now
+	}
+	// The reader goroutine hangs onto this, so it has to be done when we are,
+	// or it'll be stuck waiting to hand over a line nobody is going to read.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	u.ctx = ctx
+	lines := make(chan line)
+	u.lines = lines
+	go u.read(lines)
+
+	err := u.run(ctx, near)
+	if err == errQuit {
+		u.printf("Bye!\n")
+		return nil
+	}
+	return err
+}
+
+func (u *UI) run(ctx context.Context, near favor.LatLng) error {
+	merchant, err := u.chooseMerchant(near)
+	if err != nil {
+		return err
+	}
+	rf, err := u.composeRequest(merchant, near)
+	if err != nil {
+		return err
+	}
+	f, err := u.Client.PlaceFavor(rf)
+	if err != nil {
+		return err
+	}
+	u.printf("\nPlaced favor #%v! Keeping an eye on it for you. (ctrl+c to stop)\n", f.ID)
+	return u.track(ctx, f.ID)
+}
+
+func (u *UI) merchantLine(i int, m favor.Merchant, near favor.LatLng) string {
+	status := "hours unknown"
+	if len(m.Hours) > 0 {
+		status = "closed"
+		if m.IsOpenAt(u.Now()) {
+			status = "open"
+		}
+	}
+	distance := "?"
+	if d, err := m.DistanceFrom(near); err == nil {
+		distance = fmt.Sprintf("%.1f km", d/1000)
+	}
+	return fmt.Sprintf("%3d) %-40v %8v  %v", i+1, m.Name, distance, status)
+}
+
+// chooseMerchant lists the merchants near a point, a page at a time, and lets
+// the person search them and pick one.
+func (u *UI) chooseMerchant(near favor.LatLng) (favor.Merchant, error) {
+	u.printf("Looking for merchants near %v...\n", near)
+	found, err := u.Client.GetMerchants(near.Lat, near.Lng)
+	if err != nil {
+		return favor.Merchant{}, err
+	}
+	all := favor.Merchants(found).SortBy(favor.OpenFirst(u.Now()), favor.ByDistance(near))
+	shown := all
+	page := 0
+
+	for {
+		if len(shown) == 0 {
+			u.printf("\nNo merchants found.\n")
+		} else {
+			u.printf("\n")
+			for i := page * pageSize; i < len(shown) && i < (page+1)*pageSize; i++ {
+				u.printf("%v\n", u.merchantLine(i, shown[i], near))
+			}
+		}
+
+		answer, err := u.prompt("\n[number] pick, [n]ext, [p]revious, [s]earch <text>, [a]ll, [q]uit: ")
+		if err != nil {
+			return favor.Merchant{}, err
+		}
+
+		parts := strings.SplitN(answer, " ", 2)
+		command := strings.ToLower(parts[0])
+		switch {
+		case command == "q":
+			return favor.Merchant{}, errQuit
+		case command == "n":
+			if (page+1)*pageSize < len(shown) {
+				page++
+			}
+		case command == "p":
+			if page > 0 {
+				page--
+			}
+		case command == "a":
+			shown, page = all, 0
+		case command == "s" || command == "search":
+			if len(parts) < 2 {
+				u.printf("Search for what?\n")
+				continue
+			}
+			shown, page = favor.Merchants{}, 0
+			for _, r := range all.Search(parts[1], near) {
+				shown = append(shown, r.Merchant)
+			}
+		default:
+			n, err := strconv.Atoi(answer)
+			if err != nil || n < 1 || n > len(shown) {
+				u.printf("I don't know what %q means.\n", answer)
+				continue
+			}
+			m := shown[n-1]
+			if len(m.Hours) > 0 && !m.IsOpenAt(u.Now()) {
+				answer, err := u.prompt("%v looks closed right now. Pick it anyway? [y/N]: ", m.Name)
+				if err != nil {
+					return favor.Merchant{}, err
+				}
+				if !strings.HasPrefix(strings.ToLower(answer), "y") {
+					continue
+				}
+			}
+			return m, nil
+		}
+	}
+}
+
+var quantityPrefix = regexp.MustCompile(`^(\d+)\s*x\s+`)
+
+// parseItem turns a line like "2x Trailer Park" into a CartItem.
+func parseItem(line string) favor.CartItem {
+	item := favor.CartItem{Name: line, Quantity: 1}
+	if match := quantityPrefix.FindStringSubmatch(line); match != nil {
+		item.Quantity, _ = strconv.Atoi(match[1])
+		item.Name = line[len(match[0]):]
+	}
+	return item
+}
+
+// composeRequest asks what the person wants from the merchant and where it
+// should go, and has them confirm the whole thing before returning it.
+func (u *UI) composeRequest(m favor.Merchant, near favor.LatLng) (favor.RequestFavor, error) {
+	for {
+		u.printf("\nWhat do you want from %v? One item per line, like \"2x Trailer Park\". Leave a line blank when you're done.\n", m.Name)
+		cart := favor.Cart{}
+		for {
+			line, err := u.prompt("> ")
+			if err != nil {
+				return favor.RequestFavor{}, err
+			}
+			if line == "" {
+				break
+			}
+			cart.Add(parseItem(line))
+		}
+		if len(cart.Items) == 0 {
+			u.printf("You have to want something.\n")
+			continue
+		}
+
+		address := u.Address
+		if address.Lat == "" || address.Lng == "" {
+			address.Lat = strconv.FormatFloat(near.Lat, 'f', -1, 64)
+			address.Lng = strconv.FormatFloat(near.Lng, 'f', -1, 64)
+		}
+		var err error
+		if address.Street == "" {
+			if address.Street, err = u.prompt("Street address: "); err != nil {
+				return favor.RequestFavor{}, err
+			}
+		}
+		if address.Zipcode == "" {
+			if address.Zipcode, err = u.prompt("Zipcode: "); err != nil {
+				return favor.RequestFavor{}, err
+			}
+		}
+		notes, err := u.prompt("Notes for the runner (optional): ")
+		if err != nil {
+			return favor.RequestFavor{}, err
+		}
+		if notes != "" {
+			address.Notes = notes
+		}
+
+		rf := favor.RequestFavor{Title: m.Name, Wants: cart.Wants()}
+		if merchantID, err := strconv.Atoi(m.ID); err == nil {
+			rf.MerchantID = merchantID
+			rf.MarketID, _ = strconv.Atoi(m.MarketID)
+		}
+		if rf, err = rf.FromAddress(address); err != nil {
+			return favor.RequestFavor{}, err
+		}
+		if err := rf.Validate(u.Client.Geofence); err != nil {
+			u.printf("That won't work: %v\n", err)
+			u.Address = favor.Address{}
+			continue
+		}
+
+		u.printf("\nFrom:    %v\nWants:   %v\nDeliver: %v %v\nNotes:   %v\n",
+			rf.Title, strings.Replace(rf.Wants, "\n", "\n         ", -1), rf.Street, rf.Zipcode, rf.Notes)
+		if subtotal := cart.Subtotal(); subtotal > 0 {
+			u.printf("About:   $%.2f before tip and fees\n", subtotal)
+		}
+		answer, err := u.prompt("\nPlace this favor? [y]es, [e]dit, [q]uit: ")
+		if err != nil {
+			return favor.RequestFavor{}, err
+		}
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			return rf, nil
+		case "q", "quit":
+			return favor.RequestFavor{}, errQuit
+		}
+	}
+}
+
+// track follows a favor until it's finished, printing every change.
+func (u *UI) track(ctx context.Context, id string) error {
+	err := u.Client.WatchFavor(ctx, id, u.WatchInterval, func(f favor.Favor, changes favor.FavorChanges) error {
+		status := f.Stage
+		if f.LastStatus != "" {
+			status = fmt.Sprintf("%v (%v)", f.Stage, f.LastStatus)
+		}
+		u.printf("[%v] %v", u.Now().Format("15:04:05"), status)
+		if runner := strings.TrimSpace(f.Runner.Forename + " " + f.Runner.Surname); runner != "" {
+			u.printf(", runner: %v", runner)
+		}
+		if f.Receipt.Paid != "" {
+			u.printf(", paid: $%v", f.Receipt.Paid)
+		}
+		u.printf("\n")
+		return nil
+	})
+	if err == context.Canceled {
+		return nil
+	}
+	if err == nil {
+		u.printf("Enjoy!\n")
+	}
+	return err
+}
@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/favortest"
+)
+
+func buildTestUI(t *testing.T, input string, handler http.HandlerFunc) (*UI, *bytes.Buffer) {
+	client := favortest.NewClient(t, handler)
+
+	out := &bytes.Buffer{}
+	ui := &UI{
+		Client:        client,
+		In:            strings.NewReader(input),
+		Out:           out,
+		WatchInterval: time.Millisecond,
+		Now:           func() time.Time { return time.Date(2016, time.March, 9, 12, 0, 0, 0, time.UTC) },
+	}
+	return ui, out
+}
+
+func favorAPI(placed *url.Values) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v5/merchants":
+			fmt.Fprintln(w, `{"merchants": [
+				{"id": "1", "name": "Far Away Tacos", "lat": "30.4", "lng": "-97.7"},
+				{"id": "2", "name": "Torchy's Tacos", "lat": "30.2672", "lng": "-97.7431", "market_id": "7"}
+			]}`)
+		case r.URL.Path == "/api/v5/markets":
+			fmt.Fprintln(w, `{"markets": []}`)
+		case r.URL.Path == "/api/v5/favors/" && r.Method == http.MethodPost:
+			r.ParseForm()
+			*placed = r.PostForm
+			fmt.Fprintln(w, `{"favor": {"id": "9876", "title": "Torchy's Tacos", "stage": "pending"}}`)
+		case r.URL.Path == "/api/v5/favors/9876":
+			fmt.Fprintln(w, `{"favor": {"id": "9876", "title": "Torchy's Tacos", "stage": "delivered", "runner": {"forename": "Speedy"}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	placed := url.Values{}
+	input := strings.Join([]string{
+		"s torchys",
+		"1",
+		"2x Trailer Park",
+		"Green Chile Queso",
+		"",
+		"123 Fake St",
+		"78701",
+		"leave it at the door",
+		"y",
+	}, "\n") + "\n"
+	ui, out := buildTestUI(t, input, favorAPI(&placed))
+
+	assert.Nil(t, ui.Run(context.Background(), favor.LatLng{Lat: 30.2672, Lng: -97.7431}))
+	assert.Equal(t, "Torchy's Tacos", placed.Get("title"))
+	assert.Equal(t, "2", placed.Get("merchant_id"))
+	assert.Contains(t, placed.Get("wants"), "2x Trailer Park")
+	assert.Contains(t, placed.Get("wants"), "Green Chile Queso")
+	assert.Equal(t, "123 Fake St", placed.Get("street"))
+	assert.Equal(t, "leave it at the door", placed.Get("notes"))
+
+	output := out.String()
+	assert.Contains(t, output, "Placed favor #9876")
+	assert.Contains(t, output, "delivered, runner: Speedy")
+	assert.Contains(t, output, "Enjoy!")
+}
+
+func TestRunQuit(t *testing.T) {
+	placed := url.Values{}
+	ui, out := buildTestUI(t, "q\n", favorAPI(&placed))
+
+	assert.Nil(t, ui.Run(context.Background(), favor.LatLng{Lat: 30.2672, Lng: -97.7431}))
+	assert.Contains(t, out.String(), "Torchy's Tacos")
+	assert.Contains(t, out.String(), "Bye!")
+	assert.Empty(t, placed, "Quitting shouldn't place a favor")
+
+	ui, out = buildTestUI(t, "2\nTaco\n\n123 Fake St\n78701\n\nq\n", favorAPI(&placed))
+	assert.Nil(t, ui.Run(context.Background(), favor.LatLng{Lat: 30.2672, Lng: -97.7431}))
+	assert.Contains(t, out.String(), "Place this favor?")
+	assert.Empty(t, placed, "Quitting at the confirmation shouldn't place a favor")
+}
+
+func TestParseItem(t *testing.T) {
+	assert.Equal(t, favor.CartItem{Name: "Trailer Park", Quantity: 2}, parseItem("2x Trailer Park"))
+	assert.Equal(t, favor.CartItem{Name: "Trailer Park", Quantity: 3}, parseItem("3 x Trailer Park"))
+	assert.Equal(t, favor.CartItem{Name: "Trailer Park", Quantity: 1}, parseItem("Trailer Park"))
+	assert.Equal(t, favor.CartItem{Name: "2 tacos", Quantity: 1}, parseItem("2 tacos"))
+}
+
+func TestRunInterruptedAtPrompt(t *testing.T) {
+	placed := url.Values{}
+	ui, out := buildTestUI(t, "", favorAPI(&placed))
+
+	// nobody ever types anything, so only the context can end the session
+	in, w := io.Pipe()
+	defer w.Close()
+	ui.In = in
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	finished := make(chan error)
+	go func() { finished <- ui.Run(ctx, favor.LatLng{Lat: 30.2672, Lng: -97.7431}) }()
+
+	select {
+	case err := <-finished:
+		assert.Nil(t, err)
+		assert.Contains(t, out.String(), "Bye!")
+		assert.Empty(t, placed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run kept waiting for input after its context was done")
+	}
+}
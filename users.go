@@ -1,5 +1,12 @@
 package favor
 
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
 // User represents a user of the service
 type User struct {
 	ID         string `json:"id"`
@@ -11,3 +18,74 @@ type User struct {
 	FbID       int    `json:"fb_id"`
 	Image      string `json:"image"`
 }
+
+// String renders the user in a way that's suitable for logs, like
+// "Greg Salt <greg@example.com> (#1234)"
+func (u User) String() string {
+	s := strings.TrimSpace(fmt.Sprintf("%v %v", u.Forename, u.Surname))
+	if u.Email != "" {
+		s = strings.TrimSpace(fmt.Sprintf("%v <%v>", s, u.Email))
+	}
+	if u.ID != "" {
+		s = strings.TrimSpace(fmt.Sprintf("%v (#%v)", s, u.ID))
+	}
+	return s
+}
+
+// CreateFormString turns the editable fields of a User into an appropriate
+// POST form payload. Empty fields are left out, so that a User with only a
+// couple of fields set doesn't blank out the rest of the profile.
+func (u User) CreateFormString() url.Values {
+	v := url.Values{}
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"forename", u.Forename},
+		{"surname", u.Surname},
+		{"phone", u.Phone},
+		{"email", u.Email},
+		{"image", u.Image},
+	}
+	for _, f := range fields {
+		if f.value != "" {
+			v.Add(f.name, f.value)
+		}
+	}
+	return v
+}
+
+// GetMe is used to retrieve the profile of the user the client's token belongs to.
+// This is handy for verifying a token works, and whose account it is, at startup.
+func (c Client) GetMe() (User, error) {
+	uri := c.BuildURL("me", map[string]string{})
+	userData, err := c.makeAPIRequest("get", uri)
+	if err != nil {
+		return User{}, err
+	}
+	return unmarshalUser(userData)
+}
+
+// UpdateMe updates the profile of the user the client's token belongs to, and
+// returns the profile as the server saw it afterwards. Only the fields that
+// are set on u are changed; there's no clearing a field by leaving it empty.
+func (c Client) UpdateMe(u User) (User, error) {
+	uri := c.BuildURL("me", map[string]string{})
+	userData, err := c.makeAPIRequestWithBody("put", uri, u.CreateFormString())
+	if err != nil {
+		return User{}, err
+	}
+	return unmarshalUser(userData)
+}
+
+func unmarshalUser(userData []byte) (User, error) {
+	ur := struct {
+		User User `json:"user"`
+	}{}
+
+	err := json.Unmarshal(userData, &ur)
+	if err != nil {
+		return User{}, err
+	}
+	return ur.User, nil
+}
@@ -0,0 +1,89 @@
+package favor
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestGetMe(t *testing.T) {
+	dummyUserResponse := `
+	{
+		"user": {
+			"id": "1234",
+			"forename": "Greg",
+			"surname": "Salt",
+			"phone": "5124206969",
+			"email": "greg@example.com",
+			"countasked": "12",
+			"fb_id": 0,
+			"image": ""
+		}
+	}`
+
+	s := setupMockClient(t, respondWith(dummyUserResponse))
+
+	expectedUser := User{
+		ID:         "1234",
+		Forename:   "Greg",
+		Surname:    "Salt",
+		Phone:      "5124206969",
+		Email:      "greg@example.com",
+		Countasked: "12",
+	}
+
+	actualUser, err := s.GetMe()
+	if err != nil {
+		t.Errorf("GetMe failed with the following error: %v", err)
+	}
+	if !reflect.DeepEqual(expectedUser, actualUser) {
+		t.Errorf("Retrieved User differs from expected result.\n")
+		t.Errorf("Expected:\n%v+ \n", expectedUser)
+		t.Errorf("Received:\n%v+ \n", actualUser)
+	}
+
+	expectedString := "Greg Salt <greg@example.com> (#1234)"
+	if actualUser.String() != expectedString {
+		t.Errorf("Expected user to render as %q, instead got %q", expectedString, actualUser.String())
+	}
+}
+
+func TestUpdateMe(t *testing.T) {
+	s, recorded := setupRecordingClient(t, `{"user": {"id": "1234", "forename": "Greg", "surname": "Salt", "email": "greg@example.org"}}`)
+
+	actualUser, err := s.UpdateMe(User{ID: "1234", Email: "greg@example.org", Countasked: "12"})
+	if err != nil {
+		t.Errorf("UpdateMe failed with the following error: %v", err)
+	}
+	expectedUser := User{ID: "1234", Forename: "Greg", Surname: "Salt", Email: "greg@example.org"}
+	if !reflect.DeepEqual(expectedUser, actualUser) {
+		t.Errorf("Updated User differs from expected result.\n")
+		t.Errorf("Expected:\n%v+ \n", expectedUser)
+		t.Errorf("Received:\n%v+ \n", actualUser)
+	}
+
+	if recorded.Method != "PUT" || recorded.Path != "/api/v5/me" {
+		t.Errorf("Expected PUT /api/v5/me, but the server received %v %v", recorded.Method, recorded.Path)
+	}
+	// Only the email should be sent, or the rest of the profile gets blanked out
+	expectedForm := url.Values{"email": {"greg@example.org"}}
+	if !reflect.DeepEqual(expectedForm, recorded.Form) {
+		t.Errorf("Sent form differs from expected result.\n")
+		t.Errorf("Expected:\n%v+ \n", expectedForm)
+		t.Errorf("Received:\n%v+ \n", recorded.Form)
+	}
+
+	s.UpdateMe(User{Forename: "Gregory", Surname: "Salt", Phone: "5124206969", Email: "greg@example.com", Image: "greg.png"})
+	expectedForm = url.Values{
+		"forename": {"Gregory"},
+		"surname":  {"Salt"},
+		"phone":    {"5124206969"},
+		"email":    {"greg@example.com"},
+		"image":    {"greg.png"},
+	}
+	if !reflect.DeepEqual(expectedForm, recorded.Form) {
+		t.Errorf("Sent form differs from expected result.\n")
+		t.Errorf("Expected:\n%v+ \n", expectedForm)
+		t.Errorf("Received:\n%v+ \n", recorded.Form)
+	}
+}
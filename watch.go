@@ -0,0 +1,89 @@
+package favor
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DefaultWatchInterval is how often WatchFavor polls, unless told otherwise.
+const DefaultWatchInterval = 30 * time.Second
+
+// finishedStages are the stages a favor can't move on from. These are my best
+// guesses from the favors I've watched, since there's no documentation.
+var finishedStages = map[string]bool{
+	"completed": true,
+	"complete":  true,
+	"delivered": true,
+	"cancelled": true,
+	"canceled":  true,
+}
+
+// IsFinished returns whether the favor has reached a stage it won't move on from.
+func (f Favor) IsFinished() bool {
+	return finishedStages[strings.ToLower(strings.TrimSpace(f.Stage))]
+}
+
+// FavorChanges describes what's different between two snapshots of a favor.
+type FavorChanges struct {
+	Stage      bool
+	LastStatus bool
+	Runner     bool
+	Receipt    bool
+}
+
+// Any returns whether anything changed at all.
+func (fc FavorChanges) Any() bool {
+	return fc.Stage || fc.LastStatus || fc.Runner || fc.Receipt
+}
+
+// ChangesFrom compares a favor to an older snapshot of itself.
+func (f Favor) ChangesFrom(previous Favor) FavorChanges {
+	return FavorChanges{
+		Stage:      f.Stage != previous.Stage,
+		LastStatus: f.LastStatus != previous.LastStatus,
+		Runner:     !reflect.DeepEqual(f.Runner, previous.Runner),
+		Receipt:    f.Receipt != previous.Receipt,
+	}
+}
+
+// WatchFavor polls a favor every interval, and calls fn with the favor the first
+// time it's retrieved and every time it changes after that. Watching stops when
+// the favor is finished, when fn returns an error, or when the context is done.
+// Failed polls are retried at the next interval, unless the very first one fails.
+func (c Client) WatchFavor(ctx context.Context, id string, interval time.Duration, fn func(f Favor, changes FavorChanges) error) error {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	previous, err := c.GetFavor(id)
+	if err != nil {
+		return err
+	}
+	if err := fn(previous, FavorChanges{}); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for !previous.IsFinished() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		current, err := c.GetFavor(id)
+		if err != nil {
+			continue
+		}
+		if changes := current.ChangesFrom(previous); changes.Any() {
+			if err := fn(current, changes); err != nil {
+				return err
+			}
+		}
+		previous = current
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package favor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchFavor(t *testing.T) {
+	stages := []string{"pending", "pending", "assigned", "assigned", "delivered"}
+	var polls int32
+
+	s := setupMockClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(&polls, 1)) - 1
+		if i >= len(stages) {
+			i = len(stages) - 1
+		}
+		runner := ""
+		if stages[i] != "pending" {
+			runner = `"runner": {"id": "77", "forename": "Speedy"},`
+		}
+		fmt.Fprintf(w, `{"favor": {"id": "9876", %v "stage": %q}}`, runner, stages[i])
+	}))
+
+	seen := []string{}
+	err := s.WatchFavor(context.Background(), "9876", time.Millisecond, func(f Favor, changes FavorChanges) error {
+		seen = append(seen, f.Stage)
+		if f.Stage == "assigned" {
+			assert.True(t, changes.Stage)
+			assert.True(t, changes.Runner)
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"pending", "assigned", "delivered"}, seen)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&polls))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	atomic.StoreInt32(&polls, 0)
+	stages = []string{"pending"}
+	err = s.WatchFavor(ctx, "9876", time.Millisecond, func(f Favor, changes FavorChanges) error { return nil })
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
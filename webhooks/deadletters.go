@@ -0,0 +1,123 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// DeadLetters is where deliveries that never made it end up, so they can be
+// looked at, or tried again with Dispatcher.Redeliver. A delivery is known by
+// its envelope's ID and its endpoint's URL, since the same envelope goes to
+// every endpoint.
+type DeadLetters interface {
+	Add(d Delivery) error
+	List() ([]Delivery, error)
+	Remove(envelopeID, endpointURL string) error
+}
+
+// MemoryDeadLetters keeps dead letters in memory, which is fine for tests
+// and for anyone who doesn't mind losing them on restart.
+type MemoryDeadLetters struct {
+	lock       sync.Mutex
+	deliveries []Delivery
+}
+
+// Add sets a delivery aside.
+func (m *MemoryDeadLetters) Add(d Delivery) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.deliveries = append(m.deliveries, d)
+	return nil
+}
+
+// List returns every delivery that's been set aside, oldest first.
+func (m *MemoryDeadLetters) List() ([]Delivery, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]Delivery{}, m.deliveries...), nil
+}
+
+// Remove takes a delivery out.
+func (m *MemoryDeadLetters) Remove(envelopeID, endpointURL string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.deliveries = without(m.deliveries, envelopeID, endpointURL)
+	return nil
+}
+
+func without(deliveries []Delivery, envelopeID, endpointURL string) []Delivery {
+	kept := []Delivery{}
+	for _, d := range deliveries {
+		if d.Envelope.ID != envelopeID || d.Endpoint.URL != endpointURL {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// FileDeadLetters keeps dead letters in a JSON file, so they survive a
+// restart. The file holds endpoint secrets, so it's written as 0600.
+type FileDeadLetters struct {
+	Path string
+
+	lock sync.Mutex
+}
+
+func (f *FileDeadLetters) read() ([]Delivery, error) {
+	contents, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return []Delivery{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	deliveries := []Delivery{}
+	if err := json.Unmarshal(contents, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// write replaces the file all at once, so a crash halfway through doesn't
+// lose everything that was in it.
+func (f *FileDeadLetters) write(deliveries []Delivery) error {
+	contents, err := json.MarshalIndent(deliveries, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := f.Path + ".tmp"
+	if err := ioutil.WriteFile(tmp, contents, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.Path)
+}
+
+// Add sets a delivery aside.
+func (f *FileDeadLetters) Add(d Delivery) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	deliveries, err := f.read()
+	if err != nil {
+		return err
+	}
+	return f.write(append(deliveries, d))
+}
+
+// List returns every delivery that's been set aside, oldest first.
+func (f *FileDeadLetters) List() ([]Delivery, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.read()
+}
+
+// Remove takes a delivery out.
+func (f *FileDeadLetters) Remove(envelopeID, endpointURL string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	deliveries, err := f.read()
+	if err != nil {
+		return err
+	}
+	return f.write(without(deliveries, envelopeID, endpointURL))
+}
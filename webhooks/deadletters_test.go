@@ -0,0 +1,50 @@
+package webhooks
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testDeadLetters(t *testing.T, dl DeadLetters) {
+	deliveries, err := dl.List()
+	assert.Nil(t, err)
+	assert.Empty(t, deliveries)
+
+	assert.Nil(t, dl.Add(Delivery{Endpoint: Endpoint{URL: "http://one"}, Envelope: Envelope{ID: "a"}, Attempts: 5}))
+	assert.Nil(t, dl.Add(Delivery{Endpoint: Endpoint{URL: "http://two"}, Envelope: Envelope{ID: "a"}, Attempts: 1}))
+	deliveries, err = dl.List()
+	assert.Nil(t, err)
+	assert.Len(t, deliveries, 2)
+
+	assert.Nil(t, dl.Remove("a", "http://one"))
+	deliveries, err = dl.List()
+	assert.Nil(t, err)
+	if assert.Len(t, deliveries, 1, "Only the delivery to the named endpoint should be removed") {
+		assert.Equal(t, "http://two", deliveries[0].Endpoint.URL)
+	}
+}
+
+func TestMemoryDeadLetters(t *testing.T) {
+	testDeadLetters(t, &MemoryDeadLetters{})
+}
+
+func TestFileDeadLetters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhooks")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dead.json")
+
+	testDeadLetters(t, &FileDeadLetters{Path: path})
+
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	deliveries, err := (&FileDeadLetters{Path: path}).List()
+	assert.Nil(t, err)
+	assert.Len(t, deliveries, 1, "Dead letters should survive a restart")
+}
@@ -0,0 +1,205 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// These are the defaults for a Dispatcher's retries.
+const (
+	DefaultMaxAttempts = 5
+	DefaultBackoff     = time.Second
+)
+
+// defaultLogger is what a Dispatcher logs to if it isn't given a Logger.
+var defaultLogger = log.New(os.Stderr, "webhooks ", log.LstdFlags)
+
+// Delivery is an envelope on its way to an endpoint, along with how it went.
+type Delivery struct {
+	Endpoint  Endpoint  `json:"endpoint"`
+	Envelope  Envelope  `json:"envelope"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+	FailedAt  time.Time `json:"failed_at,omitempty"`
+}
+
+// Dispatcher sends events to endpoints. Deliveries that fail with a network
+// error, a 5xx or a 429 are retried up to MaxAttempts times, waiting Backoff
+// before the first retry and twice as long before each one after that. Other
+// 4xx responses aren't retried, since sending the same thing again won't
+// help. Deliveries that never make it are put in DeadLetters, if it's set.
+type Dispatcher struct {
+	Endpoints   []Endpoint
+	Client      *http.Client
+	MaxAttempts int
+	Backoff     time.Duration
+	DeadLetters DeadLetters
+	Logger      *log.Logger
+}
+
+func (d *Dispatcher) client() *http.Client {
+	if d.Client != nil {
+		return d.Client
+	}
+	return &http.Client{Timeout: 10 * time.Second}
+}
+
+func (d *Dispatcher) logf(format string, args ...interface{}) {
+	logger := d.Logger
+	if logger == nil {
+		logger = defaultLogger
+	}
+	logger.Printf(format, args...)
+}
+
+// Dispatch sends an event to every endpoint that wants it, all at once, and
+// returns once each of them has either taken it or been given up on. The only
+// errors returned are from failing to set deliveries aside in DeadLetters, and
+// every one of those is returned, not just the first.
+func (d *Dispatcher) Dispatch(ctx context.Context, e favor.FavorEvent) error {
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+	envelope := Envelope{ID: id, FavorEvent: e}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(d.Endpoints))
+	for i, endpoint := range d.Endpoints {
+		if !endpoint.Wants(e.Type) {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, endpoint Endpoint) {
+			defer wg.Done()
+			errs[i] = d.deliver(ctx, &Delivery{Endpoint: endpoint, Envelope: envelope})
+		}(i, endpoint)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// deliver tries a delivery until it works or it's time to give up on it.
+func (d *Dispatcher) deliver(ctx context.Context, delivery *Delivery) error {
+	maxAttempts := d.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	backoff := d.Backoff
+	if backoff <= 0 {
+		backoff = DefaultBackoff
+	}
+
+	body, err := json.Marshal(delivery.Envelope)
+	if err != nil {
+		return err
+	}
+	for {
+		delivery.Attempts++
+		retry, err := d.send(ctx, delivery.Endpoint, delivery.Envelope, body)
+		if err == nil {
+			return nil
+		}
+		delivery.LastError = err.Error()
+		if !retry || delivery.Attempts >= maxAttempts || ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	delivery.FailedAt = time.Now()
+	d.logf("giving up on delivering %v %v to %v after %d attempts: %v",
+		delivery.Envelope.Type, delivery.Envelope.ID, delivery.Endpoint.URL, delivery.Attempts, delivery.LastError)
+	if d.DeadLetters == nil {
+		return nil
+	}
+	if err := d.DeadLetters.Add(*delivery); err != nil {
+		return fmt.Errorf("Dead-lettering delivery %v failed and returned this error:\n %v", delivery.Envelope.ID, err)
+	}
+	return nil
+}
+
+// send makes a single attempt at a delivery, and returns whether it's worth
+// trying again if it fails.
+func (d *Dispatcher) send(ctx context.Context, endpoint Endpoint, envelope Envelope, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(endpoint.Secret, now, body))
+	req.Header.Set(EventHeader, string(envelope.Type))
+	req.Header.Set(DeliveryHeader, envelope.ID)
+
+	res, err := d.client().Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(res.Body, 1<<16))
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+	retry := res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("%v responded with %v", endpoint.URL, res.Status)
+}
+
+// Redeliver has another go at the deliveries in DeadLetters, and takes out the
+// ones that make it this time. The ones that still fail stay where they are.
+func (d *Dispatcher) Redeliver(ctx context.Context) (int, error) {
+	if d.DeadLetters == nil {
+		return 0, nil
+	}
+	dead, err := d.DeadLetters.List()
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range dead {
+		body, err := json.Marshal(delivery.Envelope)
+		if err != nil {
+			return delivered, err
+		}
+		if _, err := d.send(ctx, delivery.Endpoint, delivery.Envelope, body); err != nil {
+			continue
+		}
+		if err := d.DeadLetters.Remove(delivery.Envelope.ID, delivery.Endpoint.URL); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+// Run follows the tracker's favors and dispatches every event, until the
+// context is done. The tracker shouldn't be run by anything else at the same
+// time; if something else needs its events too, call Dispatch from your own
+// tracker.Run instead.
+func (d *Dispatcher) Run(ctx context.Context, tracker *favor.FavorTracker) error {
+	return tracker.Run(ctx, func(e favor.FavorEvent) error {
+		return d.Dispatch(ctx, e)
+	})
+}
@@ -0,0 +1,123 @@
+package webhooks_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verygoodsoftwarenotvirus/favor"
+	"github.com/verygoodsoftwarenotvirus/favor/webhooks"
+	"github.com/verygoodsoftwarenotvirus/favor/webhooks/webhookstest"
+)
+
+func buildTestDispatcher(endpoints ...webhooks.Endpoint) (*webhooks.Dispatcher, *webhooks.MemoryDeadLetters) {
+	dead := &webhooks.MemoryDeadLetters{}
+	return &webhooks.Dispatcher{
+		Endpoints:   endpoints,
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond,
+		DeadLetters: dead,
+		Logger:      log.New(ioutil.Discard, "", 0),
+	}, dead
+}
+
+var delivered = favor.FavorEvent{Type: favor.EventFinished, Favor: favor.Favor{ID: "9876", Stage: "delivered"}}
+
+func TestDispatch(t *testing.T) {
+	everything := webhookstest.NewServer("hunter2")
+	defer everything.Close()
+	createdOnly := webhookstest.NewServer("hunter3")
+	defer createdOnly.Close()
+	picky := createdOnly.Endpoint()
+	picky.Events = []favor.EventType{favor.EventCreated}
+
+	d, dead := buildTestDispatcher(everything.Endpoint(), picky)
+	assert.Nil(t, d.Dispatch(context.Background(), delivered))
+
+	envelopes := everything.Envelopes()
+	if assert.Len(t, envelopes, 1) {
+		assert.NotEmpty(t, envelopes[0].ID)
+		assert.Equal(t, "9876", envelopes[0].Favor.ID)
+	}
+	assert.Equal(t, 0, createdOnly.Attempts(), "Endpoints should only get the events they want")
+	list, _ := dead.List()
+	assert.Empty(t, list)
+}
+
+func TestDispatchRetries(t *testing.T) {
+	receiver := webhookstest.NewServer("hunter2")
+	defer receiver.Close()
+	d, dead := buildTestDispatcher(receiver.Endpoint())
+
+	receiver.FailWith(http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	assert.Nil(t, d.Dispatch(context.Background(), delivered))
+	assert.Equal(t, 3, receiver.Attempts())
+	assert.Len(t, receiver.Envelopes(), 1, "The delivery should make it on the third try")
+
+	receiver.FailWith(http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	assert.Nil(t, d.Dispatch(context.Background(), delivered))
+	assert.Equal(t, 6, receiver.Attempts())
+	list, _ := dead.List()
+	if assert.Len(t, list, 1, "Deliveries that run out of attempts should be dead-lettered") {
+		assert.Equal(t, 3, list[0].Attempts)
+		assert.Contains(t, list[0].LastError, "500")
+	}
+
+	receiver.FailWith(http.StatusBadRequest)
+	assert.Nil(t, d.Dispatch(context.Background(), delivered))
+	assert.Equal(t, 7, receiver.Attempts(), "4xx responses shouldn't be retried")
+	list, _ = dead.List()
+	assert.Len(t, list, 2)
+
+	n, err := d.Redeliver(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	list, _ = dead.List()
+	assert.Empty(t, list)
+	assert.Len(t, receiver.Envelopes(), 3)
+}
+
+func TestDispatchWrongSecret(t *testing.T) {
+	receiver := webhookstest.NewServer("hunter2")
+	defer receiver.Close()
+	endpoint := receiver.Endpoint()
+	endpoint.Secret = "hunter3"
+	d, dead := buildTestDispatcher(endpoint)
+
+	assert.Nil(t, d.Dispatch(context.Background(), delivered))
+	assert.Empty(t, receiver.Envelopes())
+	assert.Equal(t, 1, receiver.Attempts(), "A rejected signature is a 4xx, and shouldn't be retried")
+	list, _ := dead.List()
+	assert.Len(t, list, 1)
+}
+
+// brokenDeadLetters can't hold onto anything.
+type brokenDeadLetters struct{}
+
+func (brokenDeadLetters) Add(d webhooks.Delivery) error {
+	return fmt.Errorf("the disk is full")
+}
+func (brokenDeadLetters) List() ([]webhooks.Delivery, error)          { return nil, nil }
+func (brokenDeadLetters) Remove(envelopeID, endpointURL string) error { return nil }
+
+func TestDispatchDeadLetterErrors(t *testing.T) {
+	first := webhookstest.NewServer("hunter2")
+	defer first.Close()
+	second := webhookstest.NewServer("hunter2")
+	defer second.Close()
+	first.FailWith(http.StatusBadRequest)
+	second.FailWith(http.StatusBadRequest)
+
+	d, _ := buildTestDispatcher(first.Endpoint(), second.Endpoint())
+	d.DeadLetters = brokenDeadLetters{}
+	err := d.Dispatch(context.Background(), delivered)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, 2, strings.Count(err.Error(), "the disk is full"), "Every delivery that couldn't be set aside should be reported")
+	}
+}
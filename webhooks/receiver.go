@@ -0,0 +1,58 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Receiver is an http.Handler for the receiving end of a webhook. It checks
+// each delivery's signature before handing the envelope to Handle, and
+// responds with a 500 if Handle returns an error, so the delivery is retried.
+type Receiver struct {
+	Secret    string
+	Tolerance time.Duration
+	Handle    func(Envelope) error
+
+	// Now is only here so tests don't depend on what time it is.
+	Now func() time.Time
+}
+
+func (rc Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "deliveries are POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now, tolerance := time.Now(), rc.Tolerance
+	if rc.Now != nil {
+		now = rc.Now()
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	if err := Verify(rc.Secret, r.Header.Get(SignatureHeader), r.Header.Get(TimestampHeader), body, now, tolerance); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	envelope := Envelope{}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if rc.Handle != nil {
+		if err := rc.Handle(envelope); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,100 @@
+// Package webhooks pushes favor events to other systems as they happen, so
+// nobody has to poll. A Dispatcher takes the events a favor.FavorTracker turns
+// up and POSTs them as signed JSON to every Endpoint that wants them, retrying
+// failures and setting aside the ones that never go through.
+//
+// Every delivery is signed with an HMAC-SHA256 of the timestamp and the body,
+// using the endpoint's secret, so receivers can tell it came from us and
+// isn't a replay. Receiver checks all of that for you.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+// These are the headers every delivery comes with.
+const (
+	SignatureHeader = "X-Favor-Signature"
+	TimestampHeader = "X-Favor-Timestamp"
+	EventHeader     = "X-Favor-Event"
+	DeliveryHeader  = "X-Favor-Delivery"
+)
+
+// DefaultTolerance is how old a delivery's timestamp can be before Verify
+// considers it a replay.
+const DefaultTolerance = 5 * time.Minute
+
+// Endpoint is somewhere events get sent. Events is which types of event it
+// wants, and it gets all of them if that's empty.
+type Endpoint struct {
+	URL    string            `json:"url"`
+	Secret string            `json:"secret"`
+	Events []favor.EventType `json:"events,omitempty"`
+}
+
+// Wants returns whether the endpoint is interested in a type of event.
+func (e Endpoint) Wants(t favor.EventType) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, want := range e.Events {
+		if want == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Envelope is the body of every delivery: the event, plus an ID that's the
+// same across every retry, so receivers can ignore duplicates.
+type Envelope struct {
+	ID string `json:"id"`
+	favor.FavorEvent
+}
+
+// newID returns a random ID for an envelope.
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("Generating a delivery ID failed and returned this error:\n %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Sign returns the signature for a delivery, which looks like "sha256=<hex>".
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp.Unix())
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a delivery against its
+// body. Deliveries older than tolerance are refused, even if the signature is
+// fine, so that they can't be replayed later.
+func Verify(secret, signature, timestamp string, body []byte, now time.Time, tolerance time.Duration) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Error parsing timestamp %v into integer!:\n%v", timestamp, err)
+	}
+	sent := time.Unix(seconds, 0)
+	if age := now.Sub(sent); age > tolerance || age < -tolerance {
+		return fmt.Errorf("The delivery was sent at %v, which is too far from now to trust.", sent)
+	}
+	if !strings.HasPrefix(signature, "sha256=") {
+		return fmt.Errorf("The signature %q isn't a sha256 signature.", signature)
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, sent, body))) {
+		return fmt.Errorf("The signature doesn't match the body.")
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package webhooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verygoodsoftwarenotvirus/favor"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Unix(1457524800, 0)
+	body := []byte(`{"id": "abc"}`)
+	signature := Sign("hunter2", now, body)
+	assert.True(t, strings.HasPrefix(signature, "sha256="))
+
+	assert.Nil(t, Verify("hunter2", signature, "1457524800", body, now.Add(time.Minute), DefaultTolerance))
+	assert.NotNil(t, Verify("hunter3", signature, "1457524800", body, now, DefaultTolerance), "The wrong secret should fail")
+	assert.NotNil(t, Verify("hunter2", signature, "1457524800", []byte(`{"id": "abd"}`), now, DefaultTolerance), "A changed body should fail")
+	assert.NotNil(t, Verify("hunter2", signature, "1457524801", body, now, DefaultTolerance), "A changed timestamp should fail")
+	assert.NotNil(t, Verify("hunter2", signature, "1457524800", body, now.Add(time.Hour), DefaultTolerance), "Old deliveries should fail")
+	assert.NotNil(t, Verify("hunter2", signature, "yesterday", body, now, DefaultTolerance))
+}
+
+func TestEndpointWants(t *testing.T) {
+	assert.True(t, Endpoint{}.Wants(favor.EventCreated))
+	e := Endpoint{Events: []favor.EventType{favor.EventFinished}}
+	assert.True(t, e.Wants(favor.EventFinished))
+	assert.False(t, e.Wants(favor.EventCreated))
+}
+
+func TestReceiver(t *testing.T) {
+	now := time.Unix(1457524800, 0)
+	received := []Envelope{}
+	rc := Receiver{Secret: "hunter2", Now: func() time.Time { return now }, Handle: func(e Envelope) error {
+		received = append(received, e)
+		return nil
+	}}
+
+	body := `{"id": "abc", "type": "favor.finished", "favor": {"id": "9876"}}`
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign("hunter2", now, []byte(body)))
+	w := httptest.NewRecorder()
+	rc.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, favor.EventFinished, received[0].Type)
+		assert.Equal(t, "9876", received[0].Favor.ID)
+	}
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign("hunter3", now, []byte(body)))
+	w = httptest.NewRecorder()
+	rc.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Len(t, received, 1)
+}
@@ -0,0 +1,88 @@
+// Package webhookstest is a stand-in for somebody else's webhook endpoint, for
+// testing code that sends deliveries without needing a real one.
+package webhookstest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/verygoodsoftwarenotvirus/favor/webhooks"
+)
+
+// Server is a local webhook endpoint that checks signatures and remembers
+// every envelope it accepts. It can be told to fail, to see how the sender
+// copes.
+type Server struct {
+	URL    string
+	Secret string
+
+	server    *httptest.Server
+	lock      sync.Mutex
+	envelopes []webhooks.Envelope
+	attempts  int
+	failures  []int
+}
+
+// NewServer starts a stand-in endpoint that expects deliveries signed with
+// secret. Close it when you're done with it.
+func NewServer(secret string) *Server {
+	s := &Server{Secret: secret}
+	receiver := webhooks.Receiver{Secret: secret, Handle: func(e webhooks.Envelope) error {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		s.envelopes = append(s.envelopes, e)
+		return nil
+	}}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		s.attempts++
+		status := 0
+		if len(s.failures) > 0 {
+			status, s.failures = s.failures[0], s.failures[1:]
+		}
+		s.lock.Unlock()
+
+		if status != 0 {
+			http.Error(w, fmt.Sprintf("failing on purpose with %d", status), status)
+			return
+		}
+		receiver.ServeHTTP(w, r)
+	}))
+	s.URL = s.server.URL
+	return s
+}
+
+// Endpoint returns an endpoint that delivers to the server.
+func (s *Server) Endpoint() webhooks.Endpoint {
+	return webhooks.Endpoint{URL: s.URL, Secret: s.Secret}
+}
+
+// FailWith makes the next deliveries fail with the given status codes, one
+// per delivery, in order.
+func (s *Server) FailWith(statuses ...int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.failures = append(s.failures, statuses...)
+}
+
+// Envelopes returns every envelope the server has accepted so far.
+func (s *Server) Envelopes() []webhooks.Envelope {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]webhooks.Envelope{}, s.envelopes...)
+}
+
+// Attempts returns how many deliveries have been tried, including the ones
+// that failed.
+func (s *Server) Attempts() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.attempts
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}